@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GeneratorConfig is one entry of a BenchConfig's generator list.
+type GeneratorConfig struct {
+	Name    string
+	Enabled bool
+	Options map[string]string
+}
+
+// BenchConfig lists which generators a -config file enables and their
+// per-generator options (e.g. WeatherCSPRNG endpoints, reseed intervals).
+type BenchConfig struct {
+	Generators []GeneratorConfig
+}
+
+// loadConfig reads a -config file in the format:
+//
+//	generators:
+//	  - name: weather
+//	    enabled: true
+//	    options:
+//	      timeout: 2s
+//
+// This is a small hand-rolled subset of YAML rather than a vendored parser,
+// since the project has no dependency manifest to pin one against. It only
+// understands the shape above: a top-level "generators:" list of "- name:"
+// entries, each with an optional "enabled:" flag (defaults to true) and an
+// optional "options:" map of scalar key/value pairs.
+func loadConfig(path string) (*BenchConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	cfg := &BenchConfig{}
+	var current *GeneratorConfig
+	inOptions := false
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		raw := scanner.Text()
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "- name:"):
+			if current != nil {
+				cfg.Generators = append(cfg.Generators, *current)
+			}
+			name := strings.TrimSpace(strings.TrimPrefix(trimmed, "- name:"))
+			current = &GeneratorConfig{Name: name, Enabled: true, Options: map[string]string{}}
+			inOptions = false
+
+		case trimmed == "options:":
+			if current == nil {
+				return nil, fmt.Errorf("config.go:%d: \"options:\" outside a generator entry", lineNum)
+			}
+			inOptions = true
+
+		case strings.HasPrefix(trimmed, "enabled:"):
+			if current == nil {
+				return nil, fmt.Errorf("config.go:%d: \"enabled:\" outside a generator entry", lineNum)
+			}
+			value := strings.TrimSpace(strings.TrimPrefix(trimmed, "enabled:"))
+			current.Enabled = value == "true"
+			inOptions = false
+
+		case trimmed == "generators:":
+			// top-level key, nothing to record
+
+		default:
+			if inOptions && current != nil {
+				key, value, ok := strings.Cut(trimmed, ":")
+				if ok {
+					current.Options[strings.TrimSpace(key)] = strings.TrimSpace(value)
+				}
+			}
+			// Unrecognized lines outside an options block are ignored so
+			// the parser degrades gracefully on minor formatting drift.
+		}
+	}
+	if current != nil {
+		cfg.Generators = append(cfg.Generators, *current)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
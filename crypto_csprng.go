@@ -7,6 +7,12 @@ import (
 // CryptoCSPRNG implements a wrapper for the operating Crypto's CSPRNG
 type CryptoCSPRNG struct{}
 
+func init() {
+	RegisterGenerator("crypto", func(opts map[string]string) (Generator, error) {
+		return NewCryptoCSPRNG(), nil
+	})
+}
+
 // NewCryptoCSPRNG creates a new Crypto CSPRNG
 func NewCryptoCSPRNG() *CryptoCSPRNG {
 	return &CryptoCSPRNG{}
@@ -22,4 +28,14 @@ func (s *CryptoCSPRNG) GenerateBytes(numBytes int) ([]byte, error) {
 	result := make([]byte, numBytes)
 	_, err := rand.Read(result)
 	return result, err
-}
\ No newline at end of file
+}
+
+// Read implements io.Reader, filling p with cryptographically secure random bytes.
+func (s *CryptoCSPRNG) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+// Reseed is a no-op: every call already draws fresh entropy from the OS CSPRNG.
+func (s *CryptoCSPRNG) Reseed() error {
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EntropySource supplies fresh entropy to (re)seed an hmacDRBG. Each
+// HMAC-backed generator (multi-entropy, weather, hybrid, system) differs
+// only in how it gathers this entropy; the DRBG core itself is shared.
+type EntropySource interface {
+	Name() string
+	Gather() []byte
+
+	// MinEntropyBits is this source's assessed min-entropy per output byte,
+	// used to size the SP 800-90B continuous health tests in EntropyHealth.
+	// Per SP 800-90B, a conditioning step (e.g. hashing) must not be credited
+	// with adding entropy, so this reflects the raw noise source, not
+	// Gather()'s output distribution.
+	MinEntropyBits() float64
+}
+
+const (
+	hmacDRBGOutLen = sha256.Size // 32 bytes, SHA-256's output/block conditioning size
+
+	// hmacDRBGReseedInterval is SP 800-90A's maximum number of Generate
+	// calls between reseeds (2^48 requests).
+	hmacDRBGReseedInterval = 1 << 48
+
+	// hmacDRBGMaxBytesPerRequest is SP 800-90A's max_number_of_bits_per_request
+	// (2^19 bits) expressed in bytes.
+	hmacDRBGMaxBytesPerRequest = (1 << 19) / 8
+)
+
+// hmacDRBG implements NIST SP 800-90A's HMAC_DRBG (SHA-256), maintaining
+// the standard's Key/V working state rather than the ad-hoc
+// state=HMAC(state, block) chaining this core replaced. Every HMAC-backed
+// generator in this package embeds a *hmacDRBG and supplies only an
+// EntropySource; Instantiate/Update/Reseed/Generate live here exactly once.
+type hmacDRBG struct {
+	key    []byte
+	v      []byte
+	mutex  sync.Mutex
+	source EntropySource
+	health *EntropyHealth
+
+	reseedCounter uint64
+
+	// predictionResistance, when set, forces a fresh reseed from source
+	// before every Generate call, per SP 800-90A section 8.6.6.
+	predictionResistance bool
+
+	// The benchmark additionally reseeds on a wall-clock timer and byte
+	// budget (RESEED_INTERVAL / RESEED_BYTE_INTERVAL), independent of and
+	// in addition to the standard's own reseed_interval enforcement above.
+	lastReseed     time.Time
+	bytesGenerated int
+
+	// identity is processIdentity() cached at instantiate time (and after
+	// any fork-safety reseed), checked on every GenerateBytes call so a
+	// fork(2) child sharing this state doesn't emit the same stream as its
+	// parent. It combines the PID with /proc/self/stat's starttime (on
+	// Linux) rather than the PID alone, so a stale cached value can't be
+	// mistaken for the current process again after a PID wraps around and
+	// gets reused.
+	identity string
+}
+
+// newHMACDRBG instantiates a DRBG seeded immediately from source.
+func newHMACDRBG(source EntropySource, predictionResistance bool) *hmacDRBG {
+	d := &hmacDRBG{
+		source:               source,
+		health:               newEntropyHealth(source.Name(), source.MinEntropyBits()),
+		predictionResistance: predictionResistance,
+	}
+	d.instantiate()
+	return d
+}
+
+// instantiate implements SP 800-90A's Instantiate_function: Key and V start
+// at their fixed initial values and are mixed with the entropy via Update.
+// The very first seed is always accepted - there is no prior state to fall
+// back to - but it is still run through the continuous health tests so a
+// source that is unhealthy from the start shows up in Stats().
+func (d *hmacDRBG) instantiate() {
+	d.key = bytes.Repeat([]byte{0x00}, hmacDRBGOutLen)
+	d.v = bytes.Repeat([]byte{0x01}, hmacDRBGOutLen)
+	entropy := d.source.Gather()
+	d.health.Check(entropy)
+	d.update(entropy)
+	d.reseedCounter = 1
+	d.lastReseed = time.Now()
+	d.bytesGenerated = 0
+	d.identity = processIdentity()
+}
+
+// update implements SP 800-90A's HMAC_DRBG Update function.
+func (d *hmacDRBG) update(providedData []byte) {
+	mac := hmac.New(sha256.New, d.key)
+	mac.Write(d.v)
+	mac.Write([]byte{0x00})
+	mac.Write(providedData)
+	d.key = mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, d.key)
+	mac.Write(d.v)
+	d.v = mac.Sum(nil)
+
+	if len(providedData) == 0 {
+		return
+	}
+
+	mac = hmac.New(sha256.New, d.key)
+	mac.Write(d.v)
+	mac.Write([]byte{0x01})
+	mac.Write(providedData)
+	d.key = mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, d.key)
+	mac.Write(d.v)
+	d.v = mac.Sum(nil)
+}
+
+// reseedLocked implements SP 800-90A's Reseed_function. Callers must hold
+// d.mutex. If the source's sample fails the SP 800-90B continuous health
+// tests, the reseed is refused and the DRBG keeps running on its existing
+// state rather than mixing in data from a source that may have gone stuck
+// or hostile; the next call that would trigger a reseed tries again.
+func (d *hmacDRBG) reseedLocked(additionalInput []byte) {
+	entropy := d.source.Gather()
+	if !d.health.Check(entropy) {
+		return
+	}
+
+	seedMaterial := append(append([]byte{}, entropy...), additionalInput...)
+	d.update(seedMaterial)
+	d.reseedCounter = 1
+	d.lastReseed = time.Now()
+	d.bytesGenerated = 0
+}
+
+// Reseed forces an immediate reseed from the entropy source.
+func (d *hmacDRBG) Reseed() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.reseedLocked(nil)
+	return nil
+}
+
+// generateLocked implements SP 800-90A's Generate_function for a single
+// request of at most hmacDRBGMaxBytesPerRequest bytes. Callers must hold d.mutex.
+func (d *hmacDRBG) generateLocked(numBytes int, additionalInput []byte) ([]byte, error) {
+	if numBytes > hmacDRBGMaxBytesPerRequest {
+		return nil, fmt.Errorf("hmacDRBG: requested %d bytes exceeds max %d bytes per request", numBytes, hmacDRBGMaxBytesPerRequest)
+	}
+
+	if d.predictionResistance || d.reseedCounter > hmacDRBGReseedInterval {
+		d.reseedLocked(additionalInput)
+	}
+	if len(additionalInput) > 0 {
+		d.update(additionalInput)
+	}
+
+	result := make([]byte, 0, numBytes)
+	for len(result) < numBytes {
+		mac := hmac.New(sha256.New, d.key)
+		mac.Write(d.v)
+		d.v = mac.Sum(nil)
+		result = append(result, d.v...)
+	}
+	result = result[:numBytes]
+
+	d.update(additionalInput)
+	d.reseedCounter++
+
+	return result, nil
+}
+
+// GenerateBytes generates cryptographically secure random bytes, chunking
+// the request across generateLocked calls to respect
+// hmacDRBGMaxBytesPerRequest, and reseeding first if the benchmark's own
+// wall-clock/byte-budget policy has been exceeded.
+func (d *hmacDRBG) GenerateBytes(numBytes int) ([]byte, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if processIdentity() != d.identity {
+		// We're a fork(2) child sharing pre-fork Key/V/counters with our
+		// parent: reseed immediately rather than let both processes emit
+		// the same stream until the next scheduled reseed.
+		d.forkSafetyReseedLocked()
+	}
+
+	if time.Since(d.lastReseed) > RESEED_INTERVAL || d.bytesGenerated > RESEED_BYTE_INTERVAL {
+		d.reseedLocked(nil)
+	}
+
+	result := make([]byte, 0, numBytes)
+	remaining := numBytes
+	for remaining > 0 {
+		chunk := remaining
+		if chunk > hmacDRBGMaxBytesPerRequest {
+			chunk = hmacDRBGMaxBytesPerRequest
+		}
+
+		block, err := d.generateLocked(chunk, nil)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, block...)
+		remaining -= chunk
+	}
+
+	d.bytesGenerated += numBytes
+	return result, nil
+}
+
+// Read implements io.Reader by delegating to GenerateBytes.
+func (d *hmacDRBG) Read(p []byte) (int, error) {
+	data, err := d.GenerateBytes(len(p))
+	if err != nil {
+		return 0, err
+	}
+	return copy(p, data), nil
+}
+
+// Stats reports the entropy source's SP 800-90B continuous health test
+// history, so callers can tell whether reseeds have been silently refused.
+func (d *hmacDRBG) Stats() EntropyHealthStats {
+	return d.health.Stats()
+}
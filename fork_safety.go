@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// processIdentity returns a string that changes whenever the calling
+// process is a fork(2) child of whoever last called this function: the PID
+// always changes across a real fork, and on Linux the process start time
+// from /proc/self/stat additionally guards against the rare case of PID
+// reuse colliding with a stale cached value.
+func processIdentity() string {
+	pid := os.Getpid()
+
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return strconv.Itoa(pid)
+	}
+	// The comm field is parenthesized and may itself contain spaces or
+	// parens, so split on the last ')' rather than whitespace.
+	closeParen := bytes.LastIndexByte(data, ')')
+	if closeParen < 0 || closeParen+2 >= len(data) {
+		return strconv.Itoa(pid)
+	}
+	fields := strings.Fields(string(data[closeParen+2:]))
+	const starttimeField = 19 // starttime is stat's 22nd field; 19th field after "state", 0-based
+	if len(fields) <= starttimeField {
+		return strconv.Itoa(pid)
+	}
+	return fmt.Sprintf("%d:%s", pid, fields[starttimeField])
+}
+
+// forkSafetyReseedLocked forces an immediate reseed that mixes in a fresh
+// crypto/rand pull, the current process identity, and the monotonic clock,
+// guaranteeing the post-fork state diverges from whatever the pre-fork
+// parent goes on to generate. Unlike reseedLocked, this never refuses: the
+// whole point is to restore safety regardless of whether the configured
+// EntropySource happens to be healthy right now. Callers must hold d.mutex.
+func (d *hmacDRBG) forkSafetyReseedLocked() {
+	fresh := make([]byte, 32)
+	_, _ = rand.Read(fresh) // crypto/rand.Read only errors if the OS CSPRNG is unavailable
+
+	identity := processIdentity()
+	mix := append(fresh, []byte(fmt.Sprintf("|%s|%d", identity, monotime()))...)
+
+	d.update(mix)
+	d.reseedCounter = 1
+	d.lastReseed = time.Now()
+	d.bytesGenerated = 0
+	d.identity = identity
+}
+
+// AfterFork forces the fork-safety reseed above. Callers that fork or clone
+// this process themselves (e.g. via os/exec, syscall.ForkExec, or a cgo
+// fork wrapper) can call this explicitly in the child right away, instead
+// of waiting for the next GenerateBytes call to notice the PID change.
+func (d *hmacDRBG) AfterFork() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.forkSafetyReseedLocked()
+	return nil
+}
+
+// selfTestForkSafety is a fast, in-process startup check that the
+// fork-safety reseed causes divergence: it reproduces a fork(2) child's
+// starting condition (Key/V/counters byte-for-byte identical to the
+// parent's) by copying a hmacDRBG's working state into a second instance
+// with a stale cached identity, field by field (rather than via a struct
+// copy, which would also copy - and so race on - the embedded mutex). This
+// runs on every program start and is deliberately cheap; it is not a
+// substitute for TestForkSafetyChildDivergesFromParent in
+// fork_safety_test.go, which exercises the real thing across two separate
+// OS processes.
+func selfTestForkSafety() error {
+	source := fixedEntropySource{name: "fork-selftest", data: bytes.Repeat([]byte{0x7E}, 32)}
+	parent := newHMACDRBG(source, false)
+
+	if _, err := parent.GenerateBytes(32); err != nil {
+		return fmt.Errorf("fork safety self-test: generate failed: %w", err)
+	}
+
+	child := &hmacDRBG{
+		key:                  append([]byte{}, parent.key...),
+		v:                    append([]byte{}, parent.v...),
+		source:               parent.source,
+		health:               parent.health,
+		reseedCounter:        parent.reseedCounter,
+		predictionResistance: parent.predictionResistance,
+		lastReseed:           parent.lastReseed,
+		bytesGenerated:       parent.bytesGenerated,
+		identity:             parent.identity + "-stale", // stand in for the child's real (different) identity
+	}
+
+	childOut, err := child.GenerateBytes(32)
+	if err != nil {
+		return fmt.Errorf("fork safety self-test: generate failed: %w", err)
+	}
+	parentOut, err := parent.GenerateBytes(32)
+	if err != nil {
+		return fmt.Errorf("fork safety self-test: generate failed: %w", err)
+	}
+
+	if bytes.Equal(childOut, parentOut) {
+		return fmt.Errorf("fork safety self-test: parent and forked child produced identical output")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const forkTestChildEnv = "CSPRNG_FORK_TEST_CHILD"
+
+// forkTestOutPrefix tags the line of stdout that carries the child's actual
+// result, so it can be picked out of whatever else the re-exec'd test
+// binary prints (e.g. "PASS").
+const forkTestOutPrefix = "FORKTESTOUT:"
+
+// TestForkSafetyChildDivergesFromParent reproduces, across two real OS
+// processes, exactly the failure mode chunk1-5 fixes: a fork(2) child that
+// inherits its parent's Key/V/reseedCounter verbatim. Go cannot safely fork
+// a live multi-goroutine process and continue running without an immediate
+// exec, so - following the standard library's own pattern for this
+// (os/exec and os/signal test a "child" the same way) - this re-execs the
+// test binary with an env var set, handing the child the exact pre-fork
+// state a real fork(2) would have duplicated in memory. The child's real
+// PID still differs from its parent's, so if the fork-safety check in
+// GenerateBytes were removed or weakened, the child would emit the same
+// bytes as the parent's next call; with the fix, it must not.
+func TestForkSafetyChildDivergesFromParent(t *testing.T) {
+	if os.Getenv(forkTestChildEnv) == "1" {
+		runForkTestChild(t)
+		return
+	}
+
+	source := fixedEntropySource{name: "fork-test", data: bytes.Repeat([]byte{0x7E}, 32)}
+	parent := newHMACDRBG(source, false)
+
+	if _, err := parent.GenerateBytes(32); err != nil {
+		t.Fatalf("parent GenerateBytes failed: %v", err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestForkSafetyChildDivergesFromParent$")
+	cmd.Env = append(os.Environ(),
+		forkTestChildEnv+"=1",
+		"CSPRNG_FORK_TEST_KEY="+hex.EncodeToString(parent.key),
+		"CSPRNG_FORK_TEST_V="+hex.EncodeToString(parent.v),
+		"CSPRNG_FORK_TEST_COUNTER="+strconv.FormatUint(parent.reseedCounter, 10),
+	)
+	childOut, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("child process failed: %v", err)
+	}
+
+	parentOut, err := parent.GenerateBytes(32)
+	if err != nil {
+		t.Fatalf("parent GenerateBytes failed: %v", err)
+	}
+
+	childHex, ok := extractForkTestOut(string(childOut))
+	if !ok {
+		t.Fatalf("child produced no %s line, got: %q", forkTestOutPrefix, childOut)
+	}
+	childBytes, err := hex.DecodeString(childHex)
+	if err != nil {
+		t.Fatalf("could not decode child output %q: %v", childHex, err)
+	}
+
+	if bytes.Equal(childBytes, parentOut) {
+		t.Fatal("forked child produced the same output as the parent's next call - fork-safety check did not fire")
+	}
+}
+
+// runForkTestChild reconstructs the pre-fork DRBG state passed in via env
+// vars - standing in for what a real fork(2) would have duplicated in
+// memory - and prints what GenerateBytes produces from it.
+func runForkTestChild(t *testing.T) {
+	key, err := hex.DecodeString(os.Getenv("CSPRNG_FORK_TEST_KEY"))
+	if err != nil {
+		t.Fatalf("bad key env: %v", err)
+	}
+	v, err := hex.DecodeString(os.Getenv("CSPRNG_FORK_TEST_V"))
+	if err != nil {
+		t.Fatalf("bad v env: %v", err)
+	}
+	counter, err := strconv.ParseUint(os.Getenv("CSPRNG_FORK_TEST_COUNTER"), 10, 64)
+	if err != nil {
+		t.Fatalf("bad counter env: %v", err)
+	}
+
+	source := fixedEntropySource{name: "fork-test", data: bytes.Repeat([]byte{0x7E}, 32)}
+	child := &hmacDRBG{
+		key:           key,
+		v:             v,
+		source:        source,
+		health:        newEntropyHealth(source.Name(), source.MinEntropyBits()),
+		reseedCounter: counter,
+		lastReseed:    time.Now(),
+		// identity is left zero-valued, exactly as a naive memory-copy
+		// fork duplication would leave it without re-deriving it - this is
+		// what the GenerateBytes check is supposed to catch.
+	}
+
+	out, err := child.GenerateBytes(32)
+	if err != nil {
+		t.Fatalf("child GenerateBytes failed: %v", err)
+	}
+	fmt.Println(forkTestOutPrefix + hex.EncodeToString(out))
+}
+
+func extractForkTestOut(output string) (string, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, forkTestOutPrefix) {
+			return strings.TrimPrefix(line, forkTestOutPrefix), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// fixedEntropySource always returns the same bytes. It exists only for
+// selfTestHMACDRBG, to drive the DRBG deterministically.
+type fixedEntropySource struct {
+	name string
+	data []byte
+}
+
+func (s fixedEntropySource) Name() string            { return s.name }
+func (s fixedEntropySource) Gather() []byte          { return s.data }
+func (s fixedEntropySource) MinEntropyBits() float64 { return 8 }
+
+// selfTestHMACDRBG sanity-checks the hmacDRBG implementation against the two
+// properties SP 800-90A's known-answer tests are meant to catch:
+//
+//  1. determinism - identical entropy input produces identical output, run
+//     after run, so a CAVP-style known-answer test against this
+//     implementation would be reproducible.
+//  2. seed-sensitivity - different entropy input produces different output,
+//     so the DRBG isn't silently ignoring its seed.
+//
+// This is NOT the known-answer test chunk1-2 asked for, and does not
+// satisfy that requirement: it only checks the two properties above
+// against the implementation's own output, not against the published NIST
+// CAVP HMAC_DRBG request/response vectors (SHA-256, no prediction
+// resistance, no reseed). Vendoring and asserting against those vectors is
+// still outstanding - it needs network access to fetch them (or someone to
+// paste them in) that this environment doesn't have, and guessing at
+// vector bytes from memory would be worse than the gap it's meant to
+// close. TestHMACDRBGSelfTest wires this into `go test` so it at least
+// fails a build instead of just printing a warning; it should be treated
+// as a placeholder for the real known-answer test, not a replacement.
+func selfTestHMACDRBG() error {
+	seedA := fixedEntropySource{name: "kat-a", data: bytes.Repeat([]byte{0xA5}, 32)}
+	seedB := fixedEntropySource{name: "kat-b", data: bytes.Repeat([]byte{0x5A}, 32)}
+
+	drbg1 := newHMACDRBG(seedA, false)
+	drbg2 := newHMACDRBG(seedA, false)
+
+	out1, err := drbg1.GenerateBytes(64)
+	if err != nil {
+		return fmt.Errorf("hmacDRBG self-test: generate failed: %w", err)
+	}
+	out2, err := drbg2.GenerateBytes(64)
+	if err != nil {
+		return fmt.Errorf("hmacDRBG self-test: generate failed: %w", err)
+	}
+	if !bytes.Equal(out1, out2) {
+		return fmt.Errorf("hmacDRBG self-test: identical entropy produced different output")
+	}
+
+	drbg3 := newHMACDRBG(seedB, false)
+	out3, err := drbg3.GenerateBytes(64)
+	if err != nil {
+		return fmt.Errorf("hmacDRBG self-test: generate failed: %w", err)
+	}
+	if bytes.Equal(out1, out3) {
+		return fmt.Errorf("hmacDRBG self-test: different entropy produced identical output")
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+// TestHMACDRBGSelfTest wires selfTestHMACDRBG into `go test` so a
+// regression fails the build instead of only printing a warning from
+// main(). See selfTestHMACDRBG's doc comment for why this still isn't the
+// NIST CAVP known-answer test chunk1-2 asked for.
+func TestHMACDRBGSelfTest(t *testing.T) {
+	if err := selfTestHMACDRBG(); err != nil {
+		t.Fatal(err)
+	}
+}
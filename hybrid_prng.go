@@ -1,122 +1,97 @@
 package main
 
 import (
-	"crypto/hmac"
 	"crypto/rand"
-	"crypto/sha256"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
-	"sync"
 	"time"
 )
 
-// HybridCSPRNG implements a hybrid cryptographically secure pseudo-random number generator
-// combining weather data entropy with system entropy
-type HybridCSPRNG struct {
-	state          []byte
-	counter        uint64
-	mutex          sync.Mutex
-	client         *http.Client
-	bytesGenerated int
-	lastReseed     time.Time
+func init() {
+	// Mixing-strategy and per-source option support is deferred until a
+	// concrete need for it shows up; for now this wires the registry up to
+	// the default constructor plus prediction resistance.
+	RegisterGenerator("hybrid", func(opts map[string]string) (Generator, error) {
+		return newHybridCSPRNG(opts["prediction_resistance"] == "true"), nil
+	})
 }
 
-// NewHybridCSPRNG creates a new hybrid CSPRNG with combined entropy sources
-func NewHybridCSPRNG() *HybridCSPRNG {
-	h := &HybridCSPRNG{
-		client: &http.Client{Timeout: 1 * time.Second},
-	}
-	h.reseed()
-	return h
+// hybridEntropySource combines system entropy with external weather data,
+// so a stuck or hostile weather endpoint cannot fully determine the
+// conditioned output.
+type hybridEntropySource struct {
+	client *http.Client
 }
 
-// Name returns the generator name
-func (h *HybridCSPRNG) Name() string {
-	return "Hybrid PRNG"
+// Name identifies this entropy source.
+func (s *hybridEntropySource) Name() string {
+	return "hybrid"
 }
 
-// reseed gathers fresh entropy and mixes it into the state
-func (h *HybridCSPRNG) reseed() {
-	// Get system entropy to use as the key, as per the methodology
-	systemEntropyKey := make([]byte, 32)
-	_, err := rand.Read(systemEntropyKey)
-	if err != nil {
+// Gather returns the raw system entropy and weather-derived entropy
+// concatenated, uncondensed, so EntropyHealth can actually see a stuck
+// weather endpoint's repeated output - hmacDRBG's own Update function
+// already does the HMAC-SHA256 conditioning a DRBG's entropy input needs,
+// so HMAC-keying it again here would only hide that repetition from the
+// health tests.
+func (s *hybridEntropySource) Gather() []byte {
+	systemEntropy := make([]byte, 32)
+	if _, err := rand.Read(systemEntropy); err != nil {
 		// In a real application, this should be a fatal error.
 		// For this benchmark, we use a fallback to avoid stopping.
-		systemEntropyKey = []byte("fatal_system_entropy_read_error_")
+		systemEntropy = []byte("fatal_system_entropy_read_error_")
 	}
 
-	// Get external entropy (weather data)
-	weatherEntropy := h.getWeatherEntropy()
-
-	// Use HMAC-SHA256 keyed by system entropy to process weather data
-	mac := hmac.New(sha256.New, systemEntropyKey)
-	mac.Write(weatherEntropy)
-	newEntropy := mac.Sum(nil)
-
-	// Mix the new, conditioned entropy with the old state
-	oldStateMac := hmac.New(sha256.New, h.state)
-	oldStateMac.Write(newEntropy)
-	h.state = oldStateMac.Sum(nil)
+	return append(systemEntropy, s.getWeatherEntropy()...)
+}
 
-	h.lastReseed = time.Now()
-	h.bytesGenerated = 0
+// MinEntropyBits credits the fresh crypto/rand sample as a floor but still
+// discounts for the weather endpoint's raw min-entropy, since a degraded
+// endpoint narrows (without eliminating) the output's unpredictability.
+func (s *hybridEntropySource) MinEntropyBits() float64 {
+	return 6
 }
 
 // getWeatherEntropy fetches weather data as entropy source
-func (h *HybridCSPRNG) getWeatherEntropy() []byte {
-	start := time.Now()
-	resp, err := h.client.Get("https://wttr.in/?format=j1")
-	duration := time.Since(start)
+func (s *hybridEntropySource) getWeatherEntropy() []byte {
+	start := monotime()
+	resp, err := s.client.Get("https://wttr.in/?format=j1")
+	duration := monotime() - start
 
 	if err != nil {
-		return []byte(fmt.Sprintf("error:%d", duration.Nanoseconds()))
+		return []byte(fmt.Sprintf("error:%d", duration))
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return []byte(fmt.Sprintf("readerror:%d", duration.Nanoseconds()))
+		return []byte(fmt.Sprintf("readerror:%d", duration))
 	}
 
-	return append(body, []byte(strconv.FormatInt(duration.Nanoseconds(), 10))...)
+	return append(body, []byte(strconv.FormatInt(duration, 10))...)
 }
 
-// GenerateBytes generates cryptographically secure random bytes
-func (h *HybridCSPRNG) GenerateBytes(numBytes int) ([]byte, error) {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
-
-	// Check if reseeding is required
-	if time.Since(h.lastReseed) > RESEED_INTERVAL || h.bytesGenerated > RESEED_BYTE_INTERVAL {
-		h.reseed()
-	}
-
-	result := make([]byte, numBytes)
-	generated := 0
-
-	for generated < numBytes {
-		mac := hmac.New(sha256.New, h.state)
-		counterBytes := make([]byte, 8)
-		binary.BigEndian.PutUint64(counterBytes, h.counter)
-		mac.Write(counterBytes)
-		block := mac.Sum(nil)
-
-		toCopy := min(len(block), numBytes-generated)
-		copy(result[generated:], block[:toCopy])
+// HybridCSPRNG implements a hybrid cryptographically secure pseudo-random number generator
+// combining weather data entropy with system entropy
+type HybridCSPRNG struct {
+	*hmacDRBG
+}
 
-		generated += toCopy
-		h.counter++
+// NewHybridCSPRNG creates a new hybrid CSPRNG with combined entropy sources
+func NewHybridCSPRNG() *HybridCSPRNG {
+	return newHybridCSPRNG(false)
+}
 
-		// Update state for next block generation
-		updateMac := hmac.New(sha256.New, h.state)
-		updateMac.Write(block)
-		h.state = updateMac.Sum(nil)
+func newHybridCSPRNG(predictionResistance bool) *HybridCSPRNG {
+	source := &hybridEntropySource{
+		client: &http.Client{Timeout: 1 * time.Second},
 	}
+	return &HybridCSPRNG{hmacDRBG: newHMACDRBG(source, predictionResistance)}
+}
 
-	h.bytesGenerated += numBytes
-	return result, nil
+// Name returns the generator name
+func (h *HybridCSPRNG) Name() string {
+	return "Hybrid PRNG"
 }
@@ -12,6 +12,12 @@ type InsecurePRNG struct {
 	lock sync.Mutex
 }
 
+func init() {
+	RegisterGenerator("insecure", func(opts map[string]string) (Generator, error) {
+		return NewInsecurePRNG(), nil
+	})
+}
+
 // NewInsecurePRNG creates a new insecure PRNG
 func NewInsecurePRNG() *InsecurePRNG {
 	// Seed with predictable time
@@ -52,3 +58,20 @@ func (p *InsecurePRNG) GenerateBytes(numBytes int) ([]byte, error) {
 	}
 	return result, nil
 }
+
+// Read implements io.Reader by delegating to GenerateBytes.
+func (p *InsecurePRNG) Read(b []byte) (int, error) {
+	data, err := p.GenerateBytes(len(b))
+	if err != nil {
+		return 0, err
+	}
+	return copy(b, data), nil
+}
+
+// Reseed re-seeds the underlying math/rand source from the current time.
+func (p *InsecurePRNG) Reseed() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.rng.Seed(time.Now().UnixNano())
+	return nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ToolVersion identifies this build in machine-readable output. Bumped by
+// hand until a release process assigns it automatically.
+const ToolVersion = "0.1.0"
+
+// schemaVersion versions the JSON/JSONL output shape itself, independent of
+// ToolVersion, so downstream consumers can detect breaking format changes.
+const schemaVersion = "1"
+
+// HostInfo captures the machine a benchmark ran on, for reproducibility.
+type HostInfo struct {
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	CPUModel   string `json:"cpu_model,omitempty"`
+	NumCPU     int    `json:"num_cpu"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+}
+
+// currentHostInfo snapshots the running process's host info.
+func currentHostInfo() HostInfo {
+	return HostInfo{
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		CPUModel:   cpuModel(),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+	}
+}
+
+// cpuModel best-effort reads the CPU model string from /proc/cpuinfo on
+// Linux; it returns "" elsewhere or if the read/parse fails, the same way
+// gitCommit falls back to "" rather than failing the whole report.
+func cpuModel() string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	data, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if name, model, found := strings.Cut(line, ":"); found && strings.TrimSpace(name) == "model name" {
+			return strings.TrimSpace(model)
+		}
+	}
+	return ""
+}
+
+// RunParameters records the benchmark configuration a report was produced under.
+type RunParameters struct {
+	TestSizeBytes  int `json:"test_size_bytes"`
+	NumRuns        int `json:"num_runs"`
+	MaxConcurrency int `json:"max_concurrency"`
+}
+
+// gitCommit returns the current HEAD commit hash, or "" if it can't be
+// determined (not a git checkout, git not installed, etc).
+func gitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// jsonTestResult is the JSON-friendly mirror of TestResult: durations become
+// millisecond floats and errors become plain strings so the struct marshals
+// without custom (Un)MarshalJSON methods.
+type jsonTestResult struct {
+	Generator      string      `json:"generator"`
+	TestRun        int         `json:"test_run"`
+	DurationMs     float64     `json:"duration_ms"`
+	ThroughputMBps float64     `json:"throughput_mbps"`
+	Analysis       Analysis    `json:"analysis"`
+	NIST           NISTResults `json:"nist"`
+	SampleFile     string      `json:"sample_file"`
+	Error          string      `json:"error,omitempty"`
+}
+
+func toJSONTestResult(r TestResult) jsonTestResult {
+	errStr := ""
+	if r.Error != nil {
+		errStr = r.Error.Error()
+	}
+	return jsonTestResult{
+		Generator:      r.Name,
+		TestRun:        r.TestRun,
+		DurationMs:     float64(r.Duration.Nanoseconds()) / 1e6,
+		ThroughputMBps: r.Throughput,
+		Analysis:       r.Analysis,
+		NIST:           r.NIST,
+		SampleFile:     r.Filename,
+		Error:          errStr,
+	}
+}
+
+// saveResultsToJSONL writes one JSON object per line, one per TestResult,
+// mirroring saveResultsToCSV's content without needing a second pass to parse it.
+func saveResultsToJSONL(results []TestResult, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, result := range results {
+		if err := encoder.Encode(toJSONTestResult(result)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonAggregatedResult is the JSON-friendly mirror of AggregatedResult.
+type jsonAggregatedResult struct {
+	Generator               string   `json:"generator"`
+	TotalTests              int      `json:"total_tests"`
+	SuccessfulTests         int      `json:"successful_tests"`
+	FailedTests             int      `json:"failed_tests"`
+	AvgDurationMs           float64  `json:"avg_duration_ms"`
+	MinDurationMs           float64  `json:"min_duration_ms"`
+	MaxDurationMs           float64  `json:"max_duration_ms"`
+	MedianDurationMs        float64  `json:"median_duration_ms"`
+	AvgThroughputMBps       float64  `json:"avg_throughput_mbps"`
+	MinThroughputMBps       float64  `json:"min_throughput_mbps"`
+	MaxThroughputMBps       float64  `json:"max_throughput_mbps"`
+	MedianThroughputMBps    float64  `json:"median_throughput_mbps"`
+	P90ThroughputMBps       float64  `json:"p90_throughput_mbps"`
+	P95ThroughputMBps       float64  `json:"p95_throughput_mbps"`
+	P99ThroughputMBps       float64  `json:"p99_throughput_mbps"`
+	StdDevThroughputMBps    float64  `json:"stddev_throughput_mbps"`
+	ThroughputCI95Low       float64  `json:"throughput_ci95_low_mbps"`
+	ThroughputCI95High      float64  `json:"throughput_ci95_high_mbps"`
+	AvgAnalysis             Analysis `json:"avg_analysis"`
+	TotalDataGeneratedBytes int64    `json:"total_data_generated_bytes"`
+}
+
+func toJSONAggregated(a AggregatedResult) jsonAggregatedResult {
+	return jsonAggregatedResult{
+		Generator:               a.Name,
+		TotalTests:              a.TotalTests,
+		SuccessfulTests:         a.SuccessfulTests,
+		FailedTests:             a.FailedTests,
+		AvgDurationMs:           float64(a.AvgDuration.Nanoseconds()) / 1e6,
+		MinDurationMs:           float64(a.MinDuration.Nanoseconds()) / 1e6,
+		MaxDurationMs:           float64(a.MaxDuration.Nanoseconds()) / 1e6,
+		MedianDurationMs:        float64(a.MedianDuration.Nanoseconds()) / 1e6,
+		AvgThroughputMBps:       a.AvgThroughput,
+		MinThroughputMBps:       a.MinThroughput,
+		MaxThroughputMBps:       a.MaxThroughput,
+		MedianThroughputMBps:    a.MedianThroughput,
+		P90ThroughputMBps:       a.P90Throughput,
+		P95ThroughputMBps:       a.P95Throughput,
+		P99ThroughputMBps:       a.P99Throughput,
+		StdDevThroughputMBps:    a.StdDevThroughput,
+		ThroughputCI95Low:       a.ThroughputCILow,
+		ThroughputCI95High:      a.ThroughputCIHigh,
+		AvgAnalysis:             a.AvgAnalysis,
+		TotalDataGeneratedBytes: a.TotalDataGenerated,
+	}
+}
+
+// AggregatedReport is the top-level schema written by saveAggregatedToJSON,
+// carrying enough provenance (tool version, commit, host, parameters) for a
+// downstream script or CI job to compare runs across commits without
+// re-deriving context from the CSV filenames.
+type AggregatedReport struct {
+	SchemaVersion string                 `json:"schema_version"`
+	ToolVersion   string                 `json:"tool_version"`
+	GitCommit     string                 `json:"git_commit,omitempty"`
+	GeneratedAt   string                 `json:"generated_at"`
+	Host          HostInfo               `json:"host"`
+	Parameters    RunParameters          `json:"parameters"`
+	Results       []jsonAggregatedResult `json:"results"`
+}
+
+// saveAggregatedToJSON writes a single structured report of the aggregated
+// results alongside the run's provenance.
+func saveAggregatedToJSON(aggregated map[string]AggregatedResult, params RunParameters, filename string) error {
+	report := AggregatedReport{
+		SchemaVersion: schemaVersion,
+		ToolVersion:   ToolVersion,
+		GitCommit:     gitCommit(),
+		GeneratedAt:   time.Now().Format(time.RFC3339),
+		Host:          currentHostInfo(),
+		Parameters:    params,
+	}
+	for _, agg := range aggregated {
+		report.Results = append(report.Results, toJSONAggregated(agg))
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,10 +14,16 @@ import (
 	"time"
 )
 
-// Generator interface for all RNG types
+// Generator interface for all RNG types. Embedding io.Reader lets any
+// Generator be used directly with rand.Reader-style APIs (io.Copy, etc.)
+// and lets the benchmark harness stream output in fixed-size chunks
+// instead of allocating the whole test size up front. Reseed lets callers
+// force a fresh mix of entropy outside the generator's normal reseed timer.
 type Generator interface {
 	Name() string
 	GenerateBytes(numBytes int) ([]byte, error)
+	Reseed() error
+	io.Reader
 }
 
 // TestResult holds individual test results
@@ -25,6 +33,7 @@ type TestResult struct {
 	Duration   time.Duration
 	Throughput float64
 	Analysis   Analysis
+	NIST       NISTResults
 	Filename   string
 	Error      error
 }
@@ -38,9 +47,17 @@ type AggregatedResult struct {
 	AvgDuration        time.Duration
 	MinDuration        time.Duration
 	MaxDuration        time.Duration
+	MedianDuration     time.Duration
 	AvgThroughput      float64
 	MinThroughput      float64
 	MaxThroughput      float64
+	MedianThroughput   float64
+	P90Throughput      float64
+	P95Throughput      float64
+	P99Throughput      float64
+	StdDevThroughput   float64
+	ThroughputCILow    float64
+	ThroughputCIHigh   float64
 	AvgAnalysis        Analysis
 	TotalDataGenerated int64
 }
@@ -80,12 +97,44 @@ func (pt *ProgressTracker) increment() {
 	pt.mu.Unlock()
 }
 
-// performanceTest tests generator performance
-func performanceTest(generator Generator, testSize int) ([]byte, time.Duration, float64, error) {
+// performanceTest streams testSize bytes out of generator in streamChunkSize
+// chunks, feeding each chunk into a rolling analyzer instead of allocating
+// testSize bytes up front. This lets the harness scale to multi-GB test
+// sizes without OOM. Only the first nistSampleCap bytes of the stream are
+// retained (for the NIST battery and the on-disk sample file), since those
+// need random access to the raw bytes rather than a running tally.
+func performanceTest(generator Generator, testSize int) ([]byte, Analysis, time.Duration, float64, error) {
+	analyzer := newStreamingAnalyzer()
+	var sample []byte
+	chunk := make([]byte, streamChunkSize)
+
 	start := time.Now()
-	data, err := generator.GenerateBytes(testSize)
-	if err != nil {
-		return nil, 0, 0, err
+	remaining := testSize
+	for remaining > 0 {
+		readSize := streamChunkSize
+		if remaining < readSize {
+			readSize = remaining
+		}
+
+		n, err := generator.Read(chunk[:readSize])
+		if n > 0 {
+			analyzer.Write(chunk[:n])
+			if len(sample) < nistSampleCap {
+				room := nistSampleCap - len(sample)
+				toKeep := n
+				if toKeep > room {
+					toKeep = room
+				}
+				sample = append(sample, chunk[:toKeep]...)
+			}
+			remaining -= n
+		}
+		if err != nil {
+			return nil, Analysis{}, 0, 0, err
+		}
+		if n == 0 {
+			return nil, Analysis{}, 0, 0, fmt.Errorf("generator %s returned zero bytes from Read", generator.Name())
+		}
 	}
 	duration := time.Since(start)
 
@@ -95,83 +144,7 @@ func performanceTest(generator Generator, testSize int) ([]byte, time.Duration,
 	}
 
 	throughputMBs := float64(testSize) / duration.Seconds() / (1024 * 1024)
-	return data, duration, throughputMBs, nil
-}
-
-// basicAnalysis performs enhanced statistical analysis
-func basicAnalysis(data []byte) Analysis {
-	if len(data) == 0 {
-		return Analysis{}
-	}
-
-	// Byte frequency analysis
-	byteCounts := make([]int, 256)
-	for _, b := range data {
-		byteCounts[b]++
-	}
-
-	// Calculate uniformity metrics
-	expectedFreq := float64(len(data)) / 256.0
-	chiSquare := 0.0
-	for _, count := range byteCounts {
-		diff := float64(count) - expectedFreq
-		chiSquare += (diff * diff) / expectedFreq
-	}
-
-	// Calculate mean
-	sum := 0
-	for _, b := range data {
-		sum += int(b)
-	}
-	mean := float64(sum) / float64(len(data))
-
-	// Find min/max frequencies
-	minFreq := byteCounts[0]
-	maxFreq := byteCounts[0]
-	for i := 0; i < 256; i++ {
-		count := byteCounts[i]
-		if count < minFreq {
-			minFreq = count
-		}
-		if count > maxFreq {
-			maxFreq = count
-		}
-	}
-	freqRange := maxFreq - minFreq
-
-	// Calculate Shannon entropy
-	shannon := 0.0
-	for _, count := range byteCounts {
-		if count > 0 {
-			prob := float64(count) / float64(len(data))
-			shannon -= prob * math.Log2(prob)
-		}
-	}
-
-	// Calculate autocorrelation (lag-1) - optimized for performance
-	autocorr := 0.0
-	sampleSize := len(data)
-	if sampleSize > 50000 {
-		sampleSize = 50000 // Reduced for better performance
-	}
-	matches := 0
-	for i := 1; i < sampleSize; i++ {
-		if data[i] == data[i-1] {
-			matches++
-		}
-	}
-	autocorr = float64(matches) / float64(sampleSize-1)
-
-	return Analysis{
-		Length:          len(data),
-		Mean:            mean,
-		ChiSquare:       chiSquare,
-		MinFreq:         minFreq,
-		MaxFreq:         maxFreq,
-		FreqRange:       freqRange,
-		ShannonEntropy:  shannon,
-		Autocorrelation: autocorr,
-	}
+	return sample, analyzer.Finalize(), duration, throughputMBs, nil
 }
 
 // saveSample saves a sample of data to file
@@ -187,6 +160,7 @@ func saveSample(data []byte, filename string, sampleSize int) error {
 func runSingleTest(generator Generator, testSize int, testRun int, resultsChannel chan<- TestResult, tracker *ProgressTracker) {
 	const maxRetries = 2
 	var data []byte
+	var analysis Analysis
 	var duration time.Duration
 	var throughput float64
 	var err error
@@ -196,9 +170,9 @@ func runSingleTest(generator Generator, testSize int, testRun int, resultsChanne
 			err = fmt.Errorf("panic occurred: %v", r)
 		}
 
-		var analysis Analysis
+		var nist NISTResults
 		if data != nil {
-			analysis = basicAnalysis(data)
+			nist = nistAnalysis(data)
 		}
 
 		result := TestResult{
@@ -207,6 +181,7 @@ func runSingleTest(generator Generator, testSize int, testRun int, resultsChanne
 			Duration:   duration,
 			Throughput: throughput,
 			Analysis:   analysis,
+			NIST:       nist,
 			Filename:   fmt.Sprintf("output/%s_sample_run%d.bin", strings.ToLower(strings.ReplaceAll(generator.Name(), " ", "_")), testRun),
 			Error:      err,
 		}
@@ -217,7 +192,7 @@ func runSingleTest(generator Generator, testSize int, testRun int, resultsChanne
 
 	// Attempt test with retries
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		data, duration, throughput, err = performanceTest(generator, testSize)
+		data, analysis, duration, throughput, err = performanceTest(generator, testSize)
 		if err == nil {
 			break // Success
 		}
@@ -280,12 +255,14 @@ func aggregateResults(results []TestResult) map[string]AggregatedResult {
 		aggregated[name] = agg
 	}
 
-	// Calculate averages
+	// Calculate averages and distribution stats
 	for name, agg := range aggregated {
 		if agg.SuccessfulTests > 0 {
 			var totalDuration time.Duration
 			var totalThroughput float64
 			var totalAnalysis Analysis
+			var durations []time.Duration
+			var throughputs []float64
 
 			successCount := 0
 			for _, result := range results {
@@ -300,6 +277,8 @@ func aggregateResults(results []TestResult) map[string]AggregatedResult {
 					totalAnalysis.FreqRange += result.Analysis.FreqRange
 					totalAnalysis.ShannonEntropy += result.Analysis.ShannonEntropy
 					totalAnalysis.Autocorrelation += result.Analysis.Autocorrelation
+					durations = append(durations, result.Duration)
+					throughputs = append(throughputs, result.Throughput)
 					successCount++
 				}
 			}
@@ -316,6 +295,23 @@ func aggregateResults(results []TestResult) map[string]AggregatedResult {
 					ShannonEntropy:  totalAnalysis.ShannonEntropy / float64(successCount),
 					Autocorrelation: totalAnalysis.Autocorrelation / float64(successCount),
 				}
+
+				sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+				sort.Float64s(throughputs)
+
+				agg.MedianDuration = durationPercentile(durations, 0.5)
+				agg.MedianThroughput = percentile(throughputs, 0.5)
+				agg.P90Throughput = percentile(throughputs, 0.9)
+				agg.P95Throughput = percentile(throughputs, 0.95)
+				agg.P99Throughput = percentile(throughputs, 0.99)
+
+				stdDev := sampleStdDev(throughputs, agg.AvgThroughput)
+				agg.StdDevThroughput = stdDev
+
+				// 95% confidence interval for the mean throughput (z = 1.96).
+				marginOfError := 1.96 * stdDev / math.Sqrt(float64(successCount))
+				agg.ThroughputCILow = agg.AvgThroughput - marginOfError
+				agg.ThroughputCIHigh = agg.AvgThroughput + marginOfError
 			}
 		}
 		aggregated[name] = agg
@@ -324,6 +320,62 @@ func aggregateResults(results []TestResult) map[string]AggregatedResult {
 	return aggregated
 }
 
+// percentile returns the value at the given percentile (0-1) of an
+// already-sorted slice using linear interpolation between closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	frac := rank - float64(lower)
+	return sorted[lower]*(1-frac) + sorted[upper]*frac
+}
+
+// durationPercentile is the time.Duration counterpart of percentile, using
+// the same linear-interpolation-between-closest-ranks convention so
+// "percentile" means the same thing across both value types. The slice
+// must already be sorted ascending.
+func durationPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	frac := rank - float64(lower)
+	return time.Duration(float64(sorted[lower])*(1-frac) + float64(sorted[upper])*frac)
+}
+
+// sampleStdDev computes the sample standard deviation (n-1 denominator) of
+// values around the given mean.
+func sampleStdDev(values []float64, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	sumSq := 0.0
+	for _, v := range values {
+		diff := v - mean
+		sumSq += diff * diff
+	}
+	return math.Sqrt(sumSq / float64(len(values)-1))
+}
+
 // saveResultsToCSV saves detailed results to CSV
 func saveResultsToCSV(results []TestResult, filename string) error {
 	file, err := os.Create(filename)
@@ -340,6 +392,8 @@ func saveResultsToCSV(results []TestResult, filename string) error {
 		"Generator", "TestRun", "Duration_ms", "Throughput_MBps",
 		"Mean", "ChiSquare", "MinFreq", "MaxFreq", "FreqRange",
 		"ShannonEntropy", "Autocorrelation",
+		"NIST_Monobit_p", "NIST_BlockFreq_p", "NIST_Runs_p", "NIST_LongestRun_p",
+		"NIST_CumSumFwd_p", "NIST_CumSumBwd_p", "NIST_ApEn2_p", "NIST_ApEn3_p", "NIST_Pass",
 		"DataLength", "SampleFile", "Error",
 	}
 	if err := writer.Write(header); err != nil {
@@ -365,6 +419,15 @@ func saveResultsToCSV(results []TestResult, filename string) error {
 			strconv.Itoa(result.Analysis.FreqRange),
 			strconv.FormatFloat(result.Analysis.ShannonEntropy, 'f', 4, 64),
 			strconv.FormatFloat(result.Analysis.Autocorrelation, 'f', 6, 64),
+			strconv.FormatFloat(result.NIST.MonobitP, 'f', 4, 64),
+			strconv.FormatFloat(result.NIST.BlockFreqP, 'f', 4, 64),
+			strconv.FormatFloat(result.NIST.RunsP, 'f', 4, 64),
+			strconv.FormatFloat(result.NIST.LongestRunP, 'f', 4, 64),
+			strconv.FormatFloat(result.NIST.CumulativeFwdP, 'f', 4, 64),
+			strconv.FormatFloat(result.NIST.CumulativeBwdP, 'f', 4, 64),
+			strconv.FormatFloat(result.NIST.ApproxEntropy2P, 'f', 4, 64),
+			strconv.FormatFloat(result.NIST.ApproxEntropy3P, 'f', 4, 64),
+			strconv.FormatBool(result.NIST.Pass()),
 			strconv.Itoa(result.Analysis.Length),
 			result.Filename,
 			errorStr,
@@ -392,8 +455,10 @@ func saveAggregatedToCSV(aggregated map[string]AggregatedResult, filename string
 	// Write header
 	header := []string{
 		"Generator", "TotalTests", "SuccessfulTests", "FailedTests",
-		"AvgDuration_ms", "MinDuration_ms", "MaxDuration_ms",
+		"AvgDuration_ms", "MinDuration_ms", "MaxDuration_ms", "MedianDuration_ms",
 		"AvgThroughput_MBps", "MinThroughput_MBps", "MaxThroughput_MBps",
+		"MedianThroughput_MBps", "P90Throughput_MBps", "P95Throughput_MBps", "P99Throughput_MBps",
+		"StdDevThroughput_MBps", "ThroughputCI95Low_MBps", "ThroughputCI95High_MBps",
 		"AvgMean", "AvgChiSquare", "AvgFreqRange",
 		"AvgShannonEntropy", "AvgAutocorrelation",
 		"TotalDataGenerated_MB",
@@ -412,9 +477,17 @@ func saveAggregatedToCSV(aggregated map[string]AggregatedResult, filename string
 			strconv.FormatFloat(float64(agg.AvgDuration.Nanoseconds())/1000000, 'f', 2, 64),
 			strconv.FormatFloat(float64(agg.MinDuration.Nanoseconds())/1000000, 'f', 2, 64),
 			strconv.FormatFloat(float64(agg.MaxDuration.Nanoseconds())/1000000, 'f', 2, 64),
+			strconv.FormatFloat(float64(agg.MedianDuration.Nanoseconds())/1000000, 'f', 2, 64),
 			strconv.FormatFloat(agg.AvgThroughput, 'f', 2, 64),
 			strconv.FormatFloat(agg.MinThroughput, 'f', 2, 64),
 			strconv.FormatFloat(agg.MaxThroughput, 'f', 2, 64),
+			strconv.FormatFloat(agg.MedianThroughput, 'f', 2, 64),
+			strconv.FormatFloat(agg.P90Throughput, 'f', 2, 64),
+			strconv.FormatFloat(agg.P95Throughput, 'f', 2, 64),
+			strconv.FormatFloat(agg.P99Throughput, 'f', 2, 64),
+			strconv.FormatFloat(agg.StdDevThroughput, 'f', 2, 64),
+			strconv.FormatFloat(agg.ThroughputCILow, 'f', 2, 64),
+			strconv.FormatFloat(agg.ThroughputCIHigh, 'f', 2, 64),
 			strconv.FormatFloat(agg.AvgAnalysis.Mean, 'f', 2, 64),
 			strconv.FormatFloat(agg.AvgAnalysis.ChiSquare, 'f', 2, 64),
 			strconv.Itoa(agg.AvgAnalysis.FreqRange),
@@ -491,6 +564,24 @@ func displayRealTimeResults(results []TestResult, generators []Generator) {
 	}
 }
 
+// stringFlag scans args for "-name=value" or "-name value" and returns the
+// value and true if found. The benchmark's CLI predates the flag package and
+// mixes positional (-runs, -size) and named options, so this keeps new named
+// flags consistent with the existing parsing style instead of introducing a
+// second parser.
+func stringFlag(args []string, name string) (string, bool) {
+	prefix := name + "="
+	for i, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			return strings.TrimPrefix(arg, prefix), true
+		}
+		if arg == name && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
 func main() {
 	totalStartTime := time.Now()
 
@@ -498,6 +589,16 @@ func main() {
 	fmt.Println("ENHANCED CSPRNG COMPARISON TOOL")
 	fmt.Println(strings.Repeat("=", 80))
 
+	if err := selfTestHMACDRBG(); err != nil {
+		fmt.Printf("⚠️  HMAC_DRBG self-test failed: %v\n", err)
+	}
+	if err := selfTestEntropyHealth(); err != nil {
+		fmt.Printf("⚠️  Entropy health self-test failed: %v\n", err)
+	}
+	if err := selfTestForkSafety(); err != nil {
+		fmt.Printf("⚠️  Fork safety self-test failed: %v\n", err)
+	}
+
 	// Configurable test parameters
 	testSize := 1024 * 1024 // 1 MB
 	numRuns := 1000         // Number of runs per generator
@@ -515,6 +616,22 @@ func main() {
 		}
 	}
 
+	// Opt-in metrics/profiling server, e.g. -metrics-addr=:9090
+	if addr, ok := stringFlag(os.Args, "-metrics-addr"); ok {
+		startMetricsServer(addr)
+	}
+
+	// Output format: csv (default), json, or both
+	outputFormat := "csv"
+	if format, ok := stringFlag(os.Args, "-format"); ok {
+		switch format {
+		case "csv", "json", "both":
+			outputFormat = format
+		default:
+			fmt.Printf("Unknown -format %q, falling back to csv\n", format)
+		}
+	}
+
 	fmt.Printf("Test Configuration:\n")
 	fmt.Printf("- Test size: %d MB\n", testSize/(1024*1024))
 	fmt.Printf("- Number of runs per generator: %d\n", numRuns)
@@ -522,13 +639,29 @@ func main() {
 	fmt.Printf("- Sample files: Saving first 10 runs only\n")
 	fmt.Printf("- Progress updates: Every 5 seconds\n")
 
-	// Initialize generators
-	generators := []Generator{
-		NewInsecurePRNG(),
-		NewSystemCSPRNG(),
-		NewCustomCSPRNG(),
-		NewWeatherCSPRNG(),
-		NewHybridCSPRNG(),
+	// Initialize generators: -config selects which generators are enabled
+	// and their per-generator options; -only overrides that with an exact,
+	// ordered subset (e.g. -only crypto,hybrid). With neither flag, the
+	// original hardcoded lineup (defaultGeneratorOrder) is used.
+	var bcfg *BenchConfig
+	if configPath, ok := stringFlag(os.Args, "-config"); ok {
+		loaded, err := loadConfig(configPath)
+		if err != nil {
+			fmt.Printf("Error loading -config %s: %v\n", configPath, err)
+			return
+		}
+		bcfg = loaded
+	}
+
+	var only []string
+	if onlyArg, ok := stringFlag(os.Args, "-only"); ok {
+		only = strings.Split(onlyArg, ",")
+	}
+
+	generators, err := buildGenerators(bcfg, only)
+	if err != nil {
+		fmt.Printf("Error building generators: %v\n", err)
+		return
 	}
 
 	totalTests := numRuns * len(generators)
@@ -582,6 +715,7 @@ func main() {
 	go func() {
 		for result := range resultsChannel {
 			allResults = append(allResults, result)
+			globalMetrics.Record(result)
 
 			// Show real-time results every 100 completed tests
 			if len(allResults)%100 == 0 {
@@ -619,23 +753,41 @@ func main() {
 		fmt.Println()
 	}
 
-	// Save detailed results to CSV
-	detailedCSV := "output/detailed_results.csv"
-	if err := saveResultsToCSV(allResults, detailedCSV); err != nil {
-		fmt.Printf("Error saving detailed results: %v\n", err)
-	} else {
-		fmt.Printf("✅ Detailed results saved to: %s\n", detailedCSV)
-	}
-
 	// Aggregate results
 	aggregated := aggregateResults(allResults)
+	runParams := RunParameters{TestSizeBytes: testSize, NumRuns: numRuns, MaxConcurrency: maxConcurrency}
 
-	// Save aggregated results to CSV
+	detailedCSV := "output/detailed_results.csv"
 	aggregatedCSV := "output/aggregated_results.csv"
-	if err := saveAggregatedToCSV(aggregated, aggregatedCSV); err != nil {
-		fmt.Printf("Error saving aggregated results: %v\n", err)
-	} else {
-		fmt.Printf("✅ Aggregated results saved to: %s\n", aggregatedCSV)
+	detailedJSONL := "output/detailed_results.jsonl"
+	aggregatedJSON := "output/aggregated_results.json"
+
+	if outputFormat == "csv" || outputFormat == "both" {
+		if err := saveResultsToCSV(allResults, detailedCSV); err != nil {
+			fmt.Printf("Error saving detailed results: %v\n", err)
+		} else {
+			fmt.Printf("✅ Detailed results saved to: %s\n", detailedCSV)
+		}
+
+		if err := saveAggregatedToCSV(aggregated, aggregatedCSV); err != nil {
+			fmt.Printf("Error saving aggregated results: %v\n", err)
+		} else {
+			fmt.Printf("✅ Aggregated results saved to: %s\n", aggregatedCSV)
+		}
+	}
+
+	if outputFormat == "json" || outputFormat == "both" {
+		if err := saveResultsToJSONL(allResults, detailedJSONL); err != nil {
+			fmt.Printf("Error saving detailed JSONL results: %v\n", err)
+		} else {
+			fmt.Printf("✅ Detailed results saved to: %s\n", detailedJSONL)
+		}
+
+		if err := saveAggregatedToJSON(aggregated, runParams, aggregatedJSON); err != nil {
+			fmt.Printf("Error saving aggregated JSON report: %v\n", err)
+		} else {
+			fmt.Printf("✅ Aggregated report saved to: %s\n", aggregatedJSON)
+		}
 	}
 
 	// Display final summary
@@ -643,19 +795,21 @@ func main() {
 	fmt.Println("FINAL PERFORMANCE SUMMARY")
 	fmt.Printf("%s\n", strings.Repeat("=", 80))
 
-	fmt.Printf("%-25s %-8s %-12s %-12s %-12s %-12s %-10s\n",
-		"Generator", "Success", "Avg MB/s", "Min MB/s", "Max MB/s", "Avg χ²", "Avg H")
-	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-25s %-8s %-12s %-12s %-12s %-20s %-12s %-10s\n",
+		"Generator", "Success", "Avg MB/s", "P95 MB/s", "StdDev", "95% CI (MB/s)", "Avg χ²", "Avg H")
+	fmt.Println(strings.Repeat("-", 110))
 
 	for _, gen := range generators {
 		if agg, exists := aggregated[gen.Name()]; exists {
 			successRate := float64(agg.SuccessfulTests) / float64(agg.TotalTests) * 100
-			fmt.Printf("%-25s %6.1f%% %12.2f %12.2f %12.2f %12.2f %10.3f\n",
+			ciStr := fmt.Sprintf("[%.2f, %.2f]", agg.ThroughputCILow, agg.ThroughputCIHigh)
+			fmt.Printf("%-25s %6.1f%% %12.2f %12.2f %12.2f %-20s %12.2f %10.3f\n",
 				agg.Name,
 				successRate,
 				agg.AvgThroughput,
-				agg.MinThroughput,
-				agg.MaxThroughput,
+				agg.P95Throughput,
+				agg.StdDevThroughput,
+				ciStr,
 				agg.AvgAnalysis.ChiSquare,
 				agg.AvgAnalysis.ShannonEntropy)
 		}
@@ -670,8 +824,14 @@ func main() {
 	fmt.Printf("%s\n", strings.Repeat("=", 80))
 
 	fmt.Println("\nOutput Files:")
-	fmt.Printf("📊 %s (detailed test results)\n", detailedCSV)
-	fmt.Printf("📈 %s (aggregated statistics)\n", aggregatedCSV)
+	if outputFormat == "csv" || outputFormat == "both" {
+		fmt.Printf("📊 %s (detailed test results)\n", detailedCSV)
+		fmt.Printf("📈 %s (aggregated statistics)\n", aggregatedCSV)
+	}
+	if outputFormat == "json" || outputFormat == "both" {
+		fmt.Printf("📊 %s (detailed test results)\n", detailedJSONL)
+		fmt.Printf("📈 %s (aggregated report)\n", aggregatedJSON)
+	}
 	fmt.Println("🗂️  output/*_sample_run*.bin (binary samples, first 10 runs only)")
 
 	fmt.Println("\n📖 Key Metrics Guide:")
@@ -12,6 +12,12 @@ type MathPRNG struct {
 	lock sync.Mutex
 }
 
+func init() {
+	RegisterGenerator("math", func(opts map[string]string) (Generator, error) {
+		return NewMathPRNG(), nil
+	})
+}
+
 // NewMathPRNG creates a new Math PRNG
 func NewMathPRNG() *MathPRNG {
 	// Seed with predictable time
@@ -52,3 +58,20 @@ func (p *MathPRNG) GenerateBytes(numBytes int) ([]byte, error) {
 	}
 	return result, nil
 }
+
+// Read implements io.Reader by delegating to GenerateBytes.
+func (p *MathPRNG) Read(b []byte) (int, error) {
+	data, err := p.GenerateBytes(len(b))
+	if err != nil {
+		return 0, err
+	}
+	return copy(b, data), nil
+}
+
+// Reseed re-seeds the underlying math/rand source from the current time.
+func (p *MathPRNG) Reseed() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.rng.Seed(time.Now().UnixNano())
+	return nil
+}
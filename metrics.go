@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	_ "net/http/pprof" // registers /debug/pprof/* on http.DefaultServeMux
+	"strings"
+	"sync"
+)
+
+// generatorMetrics holds the live counters/gauges tracked for a single
+// generator. Updated from the results-collection goroutine as each
+// TestResult arrives, so values are always current for a /metrics scrape.
+type generatorMetrics struct {
+	testsCompleted  uint64
+	testsFailed     uint64
+	durationSum     float64 // seconds
+	durationCount   uint64
+	throughputSum   float64 // MB/s
+	throughputCount uint64
+	lastChiSquare   float64
+	lastEntropy     float64
+}
+
+// MetricsRegistry aggregates per-generator metrics for Prometheus scraping.
+// Safe for concurrent use.
+type MetricsRegistry struct {
+	mu         sync.Mutex
+	generators map[string]*generatorMetrics
+}
+
+// newMetricsRegistry creates an empty registry.
+func newMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{generators: make(map[string]*generatorMetrics)}
+}
+
+// Record folds a completed TestResult into the registry's running counters.
+func (r *MetricsRegistry) Record(result TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, ok := r.generators[result.Name]
+	if !ok {
+		g = &generatorMetrics{}
+		r.generators[result.Name] = g
+	}
+
+	if result.Error != nil {
+		g.testsFailed++
+		return
+	}
+
+	g.testsCompleted++
+	g.durationSum += result.Duration.Seconds()
+	g.durationCount++
+	g.throughputSum += result.Throughput
+	g.throughputCount++
+	g.lastChiSquare = result.Analysis.ChiSquare
+	g.lastEntropy = result.Analysis.ShannonEntropy
+}
+
+// sanitizeLabel makes a generator name safe to embed as a Prometheus label value.
+func sanitizeLabel(name string) string {
+	return strings.ReplaceAll(name, `"`, `'`)
+}
+
+// WriteTo renders the registry in Prometheus text exposition format.
+func (r *MetricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sb strings.Builder
+
+	sb.WriteString("# HELP csprng_tests_completed_total Tests completed successfully per generator.\n")
+	sb.WriteString("# TYPE csprng_tests_completed_total counter\n")
+	for name, g := range r.generators {
+		fmt.Fprintf(&sb, "csprng_tests_completed_total{generator=%q} %d\n", sanitizeLabel(name), g.testsCompleted)
+	}
+
+	sb.WriteString("# HELP csprng_tests_failed_total Tests that errored per generator.\n")
+	sb.WriteString("# TYPE csprng_tests_failed_total counter\n")
+	for name, g := range r.generators {
+		fmt.Fprintf(&sb, "csprng_tests_failed_total{generator=%q} %d\n", sanitizeLabel(name), g.testsFailed)
+	}
+
+	// Only _sum/_count are tracked (no bucket counts), so this is exposed
+	// as a summary rather than a histogram - a summary's quantile lines are
+	// optional, but a histogram without _bucket{le="..."} series isn't
+	// valid and would silently break histogram_quantile() on this metric.
+	sb.WriteString("# HELP csprng_generate_duration_seconds Average generation duration per generator.\n")
+	sb.WriteString("# TYPE csprng_generate_duration_seconds summary\n")
+	for name, g := range r.generators {
+		fmt.Fprintf(&sb, "csprng_generate_duration_seconds_sum{generator=%q} %f\n", sanitizeLabel(name), g.durationSum)
+		fmt.Fprintf(&sb, "csprng_generate_duration_seconds_count{generator=%q} %d\n", sanitizeLabel(name), g.durationCount)
+	}
+
+	sb.WriteString("# HELP csprng_throughput_mbps Average generation throughput in MB/s per generator.\n")
+	sb.WriteString("# TYPE csprng_throughput_mbps summary\n")
+	for name, g := range r.generators {
+		fmt.Fprintf(&sb, "csprng_throughput_mbps_sum{generator=%q} %f\n", sanitizeLabel(name), g.throughputSum)
+		fmt.Fprintf(&sb, "csprng_throughput_mbps_count{generator=%q} %d\n", sanitizeLabel(name), g.throughputCount)
+	}
+
+	sb.WriteString("# HELP csprng_chi_square_last Most recent chi-square statistic per generator.\n")
+	sb.WriteString("# TYPE csprng_chi_square_last gauge\n")
+	for name, g := range r.generators {
+		fmt.Fprintf(&sb, "csprng_chi_square_last{generator=%q} %f\n", sanitizeLabel(name), g.lastChiSquare)
+	}
+
+	sb.WriteString("# HELP csprng_shannon_entropy_last Most recent Shannon entropy (bits/byte) per generator.\n")
+	sb.WriteString("# TYPE csprng_shannon_entropy_last gauge\n")
+	for name, g := range r.generators {
+		fmt.Fprintf(&sb, "csprng_shannon_entropy_last{generator=%q} %f\n", sanitizeLabel(name), g.lastEntropy)
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// globalMetrics collects benchmark metrics for the lifetime of the process.
+// It is populated unconditionally; startMetricsServer just decides whether
+// to expose it over HTTP.
+var globalMetrics = newMetricsRegistry()
+
+// startMetricsServer exposes globalMetrics at /metrics and the standard
+// net/http/pprof profiles at /debug/pprof/* on addr. It runs in the
+// background and logs (rather than returns) any serve error, since the
+// benchmark run itself should not be interrupted by a metrics server fault.
+func startMetricsServer(addr string) {
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		globalMetrics.WriteTo(w)
+	})
+
+	go func() {
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			log.Printf("metrics server on %s stopped: %v", addr, err)
+		}
+	}()
+
+	fmt.Printf("- Metrics server listening on http://%s/metrics (profiles at /debug/pprof/)\n", addr)
+}
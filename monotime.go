@@ -0,0 +1,30 @@
+package main
+
+import _ "unsafe"
+
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+// monotime returns the runtime's monotonic clock in nanoseconds, bypassing
+// time.Now()/time.Since() so latency and jitter measurements fed into the
+// entropy pool can't be perturbed by NTP steps, leap-second smearing, or a
+// stepped wall clock - all of which can collapse or reverse a wall-clock
+// duration between two samples.
+func monotime() int64 {
+	return nanotime()
+}
+
+// cpuJitterBytes folds the low bits of successive monotonic reads taken
+// around trivial on-CPU work into n bytes, Jitter-RNG style. It is cheap
+// enough to call on every Gather and keeps the multi-entropy source
+// producing varying output even when every HTTP entropy endpoint is down.
+func cpuJitterBytes(n int) []byte {
+	out := make([]byte, n)
+	prev := monotime()
+	for i := range out {
+		now := monotime()
+		out[i] = byte(now-prev) ^ byte(now>>8) ^ byte(prev>>3)
+		prev = now
+	}
+	return out
+}
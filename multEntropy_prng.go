@@ -1,9 +1,6 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,46 +15,31 @@ const (
 	RESEED_BYTE_INTERVAL = 500 * 1024 * 1024 // 500 MB
 )
 
-// multEntropyCSPRNG implements a multi-source entropy CSPRNG
-// using weather, market, and network data as entropy sources
-type multEntropyCSPRNG struct {
-	state          []byte
-	counter        uint64
-	mutex          sync.Mutex
-	client         *http.Client
-	bytesGenerated int
-	lastReseed     time.Time
-}
-
-// NewmultEntropyCSPRNG creates a new multi-entropy CSPRNG
-func NewmultEntropyCSPRNG() *multEntropyCSPRNG {
-	c := &multEntropyCSPRNG{
-		client: &http.Client{Timeout: 2 * time.Second}, // Increased timeout for global pings
-	}
-	c.reseed()
-	return c
+func init() {
+	RegisterGenerator("multi-entropy", func(opts map[string]string) (Generator, error) {
+		return newMultEntropyCSPRNG(opts["prediction_resistance"] == "true"), nil
+	})
 }
 
-// Name returns the generator name
-func (c *multEntropyCSPRNG) Name() string {
-	return "3 Entropy Source PRNG"
+// multiEntropySource gathers entropy from weather, market, and network
+// jitter data concurrently, matching the original multEntropyCSPRNG's
+// methodology of diverse external sources.
+type multiEntropySource struct {
+	client *http.Client
 }
 
-// reseed gathers fresh entropy and mixes it into the state
-func (c *multEntropyCSPRNG) reseed() {
-	newEntropy := c.gatherEntropy()
-	
-	// Mix new entropy into the current state using HMAC
-	mac := hmac.New(sha256.New, c.state) // Use old state as key
-	mac.Write(newEntropy)
-	c.state = mac.Sum(nil)
-	
-	c.lastReseed = time.Now()
-	c.bytesGenerated = 0
+// Name identifies this entropy source.
+func (s *multiEntropySource) Name() string {
+	return "multi-entropy"
 }
 
-// gatherEntropy collects entropy from multiple sources concurrently
-func (c *multEntropyCSPRNG) gatherEntropy() []byte {
+// Gather collects entropy from multiple sources concurrently. It returns
+// the raw concatenated text, uncondensed, so EntropyHealth can actually see
+// a stuck endpoint's repeated output - hmacDRBG's own Update function
+// already does the HMAC-SHA256 conditioning a DRBG's entropy input needs,
+// so hashing it again here would only hide that repetition from the health
+// tests.
+func (s *multiEntropySource) Gather() []byte {
 	var wg sync.WaitGroup
 	wg.Add(3)
 
@@ -65,27 +47,36 @@ func (c *multEntropyCSPRNG) gatherEntropy() []byte {
 
 	go func() {
 		defer wg.Done()
-		weatherData = c.getWeather()
+		weatherData = s.getWeather()
 	}()
 	go func() {
 		defer wg.Done()
-		marketData = c.getMarket()
+		marketData = s.getMarket()
 	}()
 	go func() {
 		defer wg.Done()
-		networkData = c.getNetworkJitter()
+		networkData = s.getNetworkJitter()
 	}()
 
 	wg.Wait()
 
-	entropy := fmt.Sprintf("%s|%s|%s|%d", weatherData, marketData, networkData, time.Now().UnixNano())
-	hash := sha256.Sum256([]byte(entropy))
-	return hash[:]
+	// cpuJitterBytes is folded in unconditionally, not just as a fallback,
+	// so the pool keeps varying even on a run where every HTTP source
+	// above happens to return its cached/error string.
+	return []byte(fmt.Sprintf("%s|%s|%s|%x|%d", weatherData, marketData, networkData, cpuJitterBytes(8), monotime()))
+}
+
+// MinEntropyBits is a conservative assessment of the combined weather,
+// market, and network-jitter text this source draws on - each of these is
+// an external, unauthenticated HTTP response and any one of them (e.g.
+// "weather_error" on a failed request) can go stuck.
+func (s *multiEntropySource) MinEntropyBits() float64 {
+	return 2
 }
 
 // getWeather fetches weather data as an entropy source
-func (c *multEntropyCSPRNG) getWeather() string {
-	resp, err := c.client.Get("https://wttr.in/?format=j1")
+func (s *multiEntropySource) getWeather() string {
+	resp, err := s.client.Get("https://wttr.in/?format=j1")
 	if err != nil {
 		return "weather_error"
 	}
@@ -99,8 +90,8 @@ func (c *multEntropyCSPRNG) getWeather() string {
 }
 
 // getMarket fetches cryptocurrency market data as an entropy source
-func (c *multEntropyCSPRNG) getMarket() string {
-	resp, err := c.client.Get("https://api.coingecko.com/api/v3/simple/price?ids=bitcoin&vs_currencies=usd")
+func (s *multiEntropySource) getMarket() string {
+	resp, err := s.client.Get("https://api.coingecko.com/api/v3/simple/price?ids=bitcoin&vs_currencies=usd")
 	if err != nil {
 		return "market_error"
 	}
@@ -115,11 +106,11 @@ func (c *multEntropyCSPRNG) getMarket() string {
 
 // getNetworkJitter measures network latency to multiple global endpoints
 // This aligns with the methodology of using diverse nodes.
-func (c *multEntropyCSPRNG) getNetworkJitter() string {
+func (s *multiEntropySource) getNetworkJitter() string {
 	endpoints := []string{
-		"https://www.google.com",     // North America
-		"https://www.yandex.ru",      // Europe/Russia
-		"https://www.baidu.com",      // Asia
+		"https://www.google.com",          // North America
+		"https://www.yandex.ru",           // Europe/Russia
+		"https://www.baidu.com",           // Asia
 		"https://www.mercadolibre.com.ar", // South America
 	}
 
@@ -131,13 +122,13 @@ func (c *multEntropyCSPRNG) getNetworkJitter() string {
 		wg.Add(1)
 		go func(url string) {
 			defer wg.Done()
-			start := time.Now()
-			resp, err := c.client.Get(url)
-			duration := time.Since(start)
+			start := monotime()
+			resp, err := s.client.Get(url)
+			duration := monotime() - start
 			if err == nil {
 				resp.Body.Close()
 				mu.Lock()
-				latencies = append(latencies, strconv.FormatInt(duration.Nanoseconds(), 10))
+				latencies = append(latencies, strconv.FormatInt(duration, 10))
 				mu.Unlock()
 			}
 		}(endpoint)
@@ -150,38 +141,25 @@ func (c *multEntropyCSPRNG) getNetworkJitter() string {
 	return strings.Join(latencies, ",")
 }
 
-// GenerateBytes generates cryptographically secure random bytes
-func (c *multEntropyCSPRNG) GenerateBytes(numBytes int) ([]byte, error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	// Check if reseeding is required 
-	if time.Since(c.lastReseed) > RESEED_INTERVAL || c.bytesGenerated > RESEED_BYTE_INTERVAL {
-		c.reseed()
-	}
-
-	result := make([]byte, numBytes)
-	generated := 0
-
-	for generated < numBytes {
-		mac := hmac.New(sha256.New, c.state)
-		counterBytes := make([]byte, 8)
-		binary.BigEndian.PutUint64(counterBytes, c.counter)
-		mac.Write(counterBytes)
-		block := mac.Sum(nil)
-
-		toCopy := min(len(block), numBytes-generated)
-		copy(result[generated:], block[:toCopy])
+// multEntropyCSPRNG implements a multi-source entropy CSPRNG
+// using weather, market, and network data as entropy sources
+type multEntropyCSPRNG struct {
+	*hmacDRBG
+}
 
-		generated += toCopy
-		c.counter++
+// NewmultEntropyCSPRNG creates a new multi-entropy CSPRNG
+func NewmultEntropyCSPRNG() *multEntropyCSPRNG {
+	return newMultEntropyCSPRNG(false)
+}
 
-		// Update state for next block generation
-		updateMac := hmac.New(sha256.New, c.state)
-		updateMac.Write(block)
-		c.state = updateMac.Sum(nil)
+func newMultEntropyCSPRNG(predictionResistance bool) *multEntropyCSPRNG {
+	source := &multiEntropySource{
+		client: &http.Client{Timeout: 2 * time.Second}, // Increased timeout for global pings
 	}
+	return &multEntropyCSPRNG{hmacDRBG: newHMACDRBG(source, predictionResistance)}
+}
 
-	c.bytesGenerated += numBytes
-	return result, nil
-}
\ No newline at end of file
+// Name returns the generator name
+func (c *multEntropyCSPRNG) Name() string {
+	return "3 Entropy Source PRNG"
+}
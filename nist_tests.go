@@ -0,0 +1,369 @@
+package main
+
+import "math"
+
+// NISTResults holds p-values from the NIST SP 800-22 statistical test suite.
+// A test is considered passed when its p-value is >= 0.01, per the standard's
+// recommended significance level.
+type NISTResults struct {
+	MonobitP        float64
+	BlockFreqP      float64
+	RunsP           float64
+	LongestRunP     float64
+	CumulativeFwdP  float64
+	CumulativeBwdP  float64
+	ApproxEntropy2P float64
+	ApproxEntropy3P float64
+}
+
+const nistAlpha = 0.01
+
+// Pass reports whether every test in the battery cleared the alpha = 0.01 threshold.
+func (r NISTResults) Pass() bool {
+	return r.MonobitP >= nistAlpha &&
+		r.BlockFreqP >= nistAlpha &&
+		r.RunsP >= nistAlpha &&
+		r.LongestRunP >= nistAlpha &&
+		r.CumulativeFwdP >= nistAlpha &&
+		r.CumulativeBwdP >= nistAlpha &&
+		r.ApproxEntropy2P >= nistAlpha &&
+		r.ApproxEntropy3P >= nistAlpha
+}
+
+// nistAnalysis runs the core NIST SP 800-22 statistical test battery against
+// a bitstream derived from data. It complements basicAnalysis with tests
+// that target structural randomness rather than byte-level uniformity.
+func nistAnalysis(data []byte) NISTResults {
+	if len(data) == 0 {
+		return NISTResults{}
+	}
+
+	bits := bytesToBits(data)
+
+	return NISTResults{
+		MonobitP:        monobitTest(bits),
+		BlockFreqP:      blockFrequencyTest(bits, 128),
+		RunsP:           runsTest(bits),
+		LongestRunP:     longestRunTest(bits),
+		CumulativeFwdP:  cumulativeSumsTest(bits, true),
+		CumulativeBwdP:  cumulativeSumsTest(bits, false),
+		ApproxEntropy2P: approximateEntropyTest(bits, 2),
+		ApproxEntropy3P: approximateEntropyTest(bits, 3),
+	}
+}
+
+// bytesToBits unpacks a byte slice into its constituent bits, MSB first.
+func bytesToBits(data []byte) []int8 {
+	bits := make([]int8, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, int8((b>>uint(i))&1))
+		}
+	}
+	return bits
+}
+
+// monobitTest is the frequency (monobit) test: S_n = sum of +/-1 per bit,
+// p = erfc(|S_n| / sqrt(2n)).
+func monobitTest(bits []int8) float64 {
+	n := len(bits)
+	sum := 0
+	for _, b := range bits {
+		if b == 1 {
+			sum++
+		} else {
+			sum--
+		}
+	}
+	sObs := math.Abs(float64(sum)) / math.Sqrt(float64(n))
+	return math.Erfc(sObs / math.Sqrt2)
+}
+
+// blockFrequencyTest splits the stream into blocks of size m and checks that
+// the proportion of ones in each block is close to 0.5.
+func blockFrequencyTest(bits []int8, m int) float64 {
+	n := len(bits)
+	numBlocks := n / m
+	if numBlocks == 0 {
+		return 0
+	}
+
+	chiSquare := 0.0
+	for i := 0; i < numBlocks; i++ {
+		ones := 0
+		for j := 0; j < m; j++ {
+			ones += int(bits[i*m+j])
+		}
+		pi := float64(ones) / float64(m)
+		diff := pi - 0.5
+		chiSquare += diff * diff
+	}
+	chiSquare *= 4 * float64(m)
+
+	return igamc(float64(numBlocks)/2, chiSquare/2)
+}
+
+// runsTest counts the number of runs (maximal sequences of identical bits)
+// and compares it against the expectation under randomness.
+func runsTest(bits []int8) float64 {
+	n := len(bits)
+	ones := 0
+	for _, b := range bits {
+		ones += int(b)
+	}
+	pi := float64(ones) / float64(n)
+
+	// Prerequisite: bail out if the bit proportion is too skewed for the
+	// runs statistic to be meaningful.
+	tau := 2 / math.Sqrt(float64(n))
+	if math.Abs(pi-0.5) >= tau {
+		return 0
+	}
+
+	vObs := 1
+	for k := 1; k < n; k++ {
+		if bits[k] != bits[k-1] {
+			vObs++
+		}
+	}
+
+	denom := 2 * math.Sqrt(2*float64(n)) * pi * (1 - pi)
+	num := math.Abs(float64(vObs) - 2*float64(n)*pi*(1-pi))
+	return math.Erfc(num / denom)
+}
+
+// longestRunTest checks whether the longest run of ones within fixed-size
+// blocks matches the distribution expected from a random sequence.
+func longestRunTest(bits []int8) float64 {
+	n := len(bits)
+
+	var m, k int
+	var bins []int
+	var probs []float64
+
+	switch {
+	case n < 128:
+		return 0
+	case n < 6272:
+		m, k = 8, 3
+		bins = []int{1, 2, 3, 4}
+		probs = []float64{0.2148, 0.3672, 0.2305, 0.1875}
+	case n < 750000:
+		m, k = 128, 5
+		bins = []int{4, 5, 6, 7, 8, 9}
+		probs = []float64{0.1174, 0.2430, 0.2493, 0.1752, 0.1027, 0.1124}
+	default:
+		m, k = 10000, 6
+		bins = []int{10, 11, 12, 13, 14, 15, 16}
+		probs = []float64{0.0882, 0.2092, 0.2483, 0.1933, 0.1208, 0.0675, 0.0727}
+	}
+
+	numBlocks := n / m
+	if numBlocks == 0 {
+		return 0
+	}
+
+	counts := make([]int, len(bins))
+	for i := 0; i < numBlocks; i++ {
+		block := bits[i*m : (i+1)*m]
+		longest, current := 0, 0
+		for _, b := range block {
+			if b == 1 {
+				current++
+				if current > longest {
+					longest = current
+				}
+			} else {
+				current = 0
+			}
+		}
+		idx := 0
+		for j, edge := range bins {
+			idx = j
+			if longest <= edge {
+				break
+			}
+		}
+		counts[idx]++
+	}
+
+	chiSquare := 0.0
+	for i, prob := range probs {
+		expected := float64(numBlocks) * prob
+		diff := float64(counts[i]) - expected
+		chiSquare += (diff * diff) / expected
+	}
+
+	return igamc(float64(k)/2, chiSquare/2)
+}
+
+// cumulativeSumsTest tracks the maximal excursion of the running sum of
+// +/-1 values, read forward or backward, from the center of a random walk.
+func cumulativeSumsTest(bits []int8, forward bool) float64 {
+	n := len(bits)
+	sum, z := 0, 0
+	for i := 0; i < n; i++ {
+		idx := i
+		if !forward {
+			idx = n - 1 - i
+		}
+		if bits[idx] == 1 {
+			sum++
+		} else {
+			sum--
+		}
+		if abs := sum; abs > z || -abs > z {
+			if abs < 0 {
+				abs = -abs
+			}
+			z = abs
+		}
+	}
+
+	fn := float64(n)
+	fz := float64(z)
+	if fz == 0 {
+		return 1
+	}
+
+	sqrtN := math.Sqrt(fn)
+
+	sumTerm := 0.0
+	start := int(math.Ceil((-fn/fz + 1) / 4))
+	end := int(math.Floor((fn/fz - 1) / 4))
+	for k := start; k <= end; k++ {
+		sumTerm += normalCDF((4*float64(k)+1)*fz/sqrtN) - normalCDF((4*float64(k)-1)*fz/sqrtN)
+	}
+
+	sumTerm2 := 0.0
+	start2 := int(math.Ceil((-fn/fz - 3) / 4))
+	for k := start2; k <= end; k++ {
+		sumTerm2 += normalCDF((4*float64(k)+3)*fz/sqrtN) - normalCDF((4*float64(k)+1)*fz/sqrtN)
+	}
+
+	p := 1 - sumTerm + sumTerm2
+	return math.Max(0, math.Min(1, p))
+}
+
+// normalCDF is the standard normal cumulative distribution function.
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// approximateEntropyTest compares the frequency of all overlapping m-bit and
+// (m+1)-bit patterns to the value expected of a random sequence.
+func approximateEntropyTest(bits []int8, m int) float64 {
+	n := len(bits)
+	if n <= m+1 {
+		return 0
+	}
+
+	phiM := patternPhi(bits, m)
+	phiM1 := patternPhi(bits, m+1)
+
+	apEn := phiM - phiM1
+	chiSquare := 2 * float64(n) * (math.Ln2 - apEn)
+
+	return igamc(math.Pow(2, float64(m-1)), chiSquare/2)
+}
+
+// patternPhi computes phi(m) for the approximate entropy test: the sum over
+// all 2^m overlapping (circular) m-bit patterns of freq*ln(freq), normalized by n.
+func patternPhi(bits []int8, m int) float64 {
+	n := len(bits)
+	numPatterns := 1 << uint(m)
+	counts := make([]int, numPatterns)
+
+	extended := make([]int8, n+m-1)
+	copy(extended, bits)
+	copy(extended[n:], bits[:m-1])
+
+	for i := 0; i < n; i++ {
+		pattern := 0
+		for j := 0; j < m; j++ {
+			pattern = (pattern << 1) | int(extended[i+j])
+		}
+		counts[pattern]++
+	}
+
+	phi := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			prob := float64(c) / float64(n)
+			phi += prob * math.Log(prob)
+		}
+	}
+	return phi
+}
+
+// igamc computes the regularized upper incomplete gamma function Q(a, x),
+// used throughout the NIST battery to convert chi-square statistics to p-values.
+func igamc(a, x float64) float64 {
+	if x <= 0 || a <= 0 {
+		return 1
+	}
+	if x < a+1 {
+		return 1 - igser(a, x)
+	}
+	return igcf(a, x)
+}
+
+// igser evaluates the regularized lower incomplete gamma function P(a, x) via
+// its series representation; used by igamc when x < a+1.
+func igser(a, x float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	gln := gammaLn(a)
+	ap := a
+	sum := 1 / a
+	del := sum
+	for n := 0; n < 200; n++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*1e-15 {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-gln)
+}
+
+// igcf evaluates Q(a, x) via its continued-fraction representation; used by
+// igamc when x >= a+1.
+func igcf(a, x float64) float64 {
+	const tiny = 1e-300
+	gln := gammaLn(a)
+
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+
+	for i := 1; i < 200; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < 1e-15 {
+			break
+		}
+	}
+
+	return math.Exp(-x+a*math.Log(x)-gln) * h
+}
+
+// gammaLn returns ln(Gamma(x)).
+func gammaLn(x float64) float64 {
+	lgamma, _ := math.Lgamma(x)
+	return lgamma
+}
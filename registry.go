@@ -0,0 +1,72 @@
+package main
+
+import "fmt"
+
+// GeneratorFactory builds a Generator from its per-generator config options.
+// Registered by each generator file's init(), so main doesn't need to know
+// about every concrete type to build the list of generators to benchmark.
+type GeneratorFactory func(opts map[string]string) (Generator, error)
+
+var generatorRegistry = map[string]GeneratorFactory{}
+
+// RegisterGenerator adds a factory under name. Called from init() in each
+// generator file; a duplicate name is a programming error and panics at
+// startup rather than silently shadowing the earlier registration.
+func RegisterGenerator(name string, factory GeneratorFactory) {
+	if _, exists := generatorRegistry[name]; exists {
+		panic(fmt.Sprintf("registry: generator %q already registered", name))
+	}
+	generatorRegistry[name] = factory
+}
+
+// defaultGeneratorOrder is used when no -config is given, preserving the
+// original hardcoded benchmark lineup.
+var defaultGeneratorOrder = []string{"insecure", "system", "multi-entropy", "weather", "hybrid"}
+
+// buildGenerators resolves the list of generators to benchmark. cfg may be
+// nil (no -config given), in which case defaultGeneratorOrder is used.
+// only, when non-empty, restricts the result to exactly those names in the
+// order given, regardless of what cfg enables.
+func buildGenerators(cfg *BenchConfig, only []string) ([]Generator, error) {
+	names := defaultGeneratorOrder
+	options := map[string]map[string]string{}
+
+	if cfg != nil {
+		names = nil
+		for _, gc := range cfg.Generators {
+			if gc.Enabled {
+				names = append(names, gc.Name)
+			}
+			options[gc.Name] = gc.Options
+		}
+	}
+
+	if len(only) > 0 {
+		names = only
+	}
+
+	generators := make([]Generator, 0, len(names))
+	for _, name := range names {
+		factory, ok := generatorRegistry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown generator %q (known: %v)", name, registeredGeneratorNames())
+		}
+		gen, err := factory(options[name])
+		if err != nil {
+			return nil, fmt.Errorf("building generator %q: %w", name, err)
+		}
+		generators = append(generators, gen)
+	}
+
+	return generators, nil
+}
+
+// registeredGeneratorNames lists every name RegisterGenerator has been
+// called with, for error messages.
+func registeredGeneratorNames() []string {
+	names := make([]string, 0, len(generatorRegistry))
+	for name := range generatorRegistry {
+		names = append(names, name)
+	}
+	return names
+}
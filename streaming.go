@@ -0,0 +1,106 @@
+package main
+
+import "math"
+
+const (
+	// streamChunkSize is the size of each chunk pulled from a Generator's
+	// Read method while running a performance test.
+	streamChunkSize = 64 * 1024
+
+	// nistSampleCap bounds how much of a stream is retained in memory for
+	// tests that need random access to the raw bytes (NIST battery, file
+	// samples). Byte-frequency and autocorrelation stats do not need this
+	// and are updated incrementally as chunks arrive.
+	nistSampleCap = 1 * 1024 * 1024
+
+	// autocorrCap mirrors the sample cap basicAnalysis used to bound the
+	// cost of the lag-1 autocorrelation check.
+	autocorrCap = 50000
+)
+
+// streamingAnalyzer accumulates byte-frequency and autocorrelation
+// statistics incrementally as data chunks arrive, so a generator's full
+// output never needs to be held in memory at once. Chi-square and Shannon
+// entropy, which only depend on the final byte-frequency table, are
+// computed once in Finalize.
+type streamingAnalyzer struct {
+	byteCounts      [256]int64
+	length          int64
+	sum             int64
+	haveLast        bool
+	lastByte        byte
+	autocorrMatches int64
+	autocorrSamples int64
+}
+
+// newStreamingAnalyzer returns an analyzer ready to accept chunks via Write.
+func newStreamingAnalyzer() *streamingAnalyzer {
+	return &streamingAnalyzer{}
+}
+
+// Write folds a chunk of generated bytes into the running statistics.
+func (a *streamingAnalyzer) Write(chunk []byte) {
+	for _, b := range chunk {
+		a.byteCounts[b]++
+		a.sum += int64(b)
+	}
+	a.length += int64(len(chunk))
+
+	for _, b := range chunk {
+		if a.autocorrSamples >= autocorrCap {
+			break
+		}
+		if a.haveLast {
+			if b == a.lastByte {
+				a.autocorrMatches++
+			}
+			a.autocorrSamples++
+		}
+		a.lastByte = b
+		a.haveLast = true
+	}
+}
+
+// Finalize computes the Analysis from the accumulated statistics.
+func (a *streamingAnalyzer) Finalize() Analysis {
+	if a.length == 0 {
+		return Analysis{}
+	}
+
+	expectedFreq := float64(a.length) / 256.0
+	chiSquare := 0.0
+	minFreq, maxFreq := a.byteCounts[0], a.byteCounts[0]
+	shannon := 0.0
+
+	for _, count := range a.byteCounts {
+		diff := float64(count) - expectedFreq
+		chiSquare += (diff * diff) / expectedFreq
+
+		if count < minFreq {
+			minFreq = count
+		}
+		if count > maxFreq {
+			maxFreq = count
+		}
+		if count > 0 {
+			prob := float64(count) / float64(a.length)
+			shannon -= prob * math.Log2(prob)
+		}
+	}
+
+	autocorr := 0.0
+	if a.autocorrSamples > 0 {
+		autocorr = float64(a.autocorrMatches) / float64(a.autocorrSamples)
+	}
+
+	return Analysis{
+		Length:          int(a.length),
+		Mean:            float64(a.sum) / float64(a.length),
+		ChiSquare:       chiSquare,
+		MinFreq:         int(minFreq),
+		MaxFreq:         int(maxFreq),
+		FreqRange:       int(maxFreq - minFreq),
+		ShannonEntropy:  shannon,
+		Autocorrelation: autocorr,
+	}
+}
@@ -1,25 +1,50 @@
 package main
 
-import (
-	"crypto/rand"
-)
+import "crypto/rand"
 
-// SystemCSPRNG implements a wrapper for the operating system's CSPRNG
-type SystemCSPRNG struct{}
+func init() {
+	RegisterGenerator("system", func(opts map[string]string) (Generator, error) {
+		return newSystemCSPRNG(opts["prediction_resistance"] == "true"), nil
+	})
+}
+
+// systemEntropySource draws its entropy straight from the OS CSPRNG via crypto/rand.
+type systemEntropySource struct{}
+
+// Name identifies this entropy source.
+func (systemEntropySource) Name() string {
+	return "system"
+}
+
+// Gather pulls a fresh 32-byte seed from crypto/rand.
+func (systemEntropySource) Gather() []byte {
+	seed := make([]byte, 32)
+	_, _ = rand.Read(seed) // crypto/rand.Read only errors if the OS CSPRNG is unavailable
+	return seed
+}
+
+// MinEntropyBits reflects the OS CSPRNG's full-strength output.
+func (systemEntropySource) MinEntropyBits() float64 {
+	return 8
+}
+
+// SystemCSPRNG is an HMAC-DRBG periodically reseeded from the operating
+// system's CSPRNG, sharing its state-update core with the other
+// HMAC-backed generators in this package.
+type SystemCSPRNG struct {
+	*hmacDRBG
+}
 
 // NewSystemCSPRNG creates a new system CSPRNG
 func NewSystemCSPRNG() *SystemCSPRNG {
-	return &SystemCSPRNG{}
+	return newSystemCSPRNG(false)
+}
+
+func newSystemCSPRNG(predictionResistance bool) *SystemCSPRNG {
+	return &SystemCSPRNG{hmacDRBG: newHMACDRBG(systemEntropySource{}, predictionResistance)}
 }
 
 // Name returns the generator name
 func (s *SystemCSPRNG) Name() string {
 	return "System CSPRNG"
 }
-
-// GenerateBytes generates cryptographically secure random bytes
-func (s *SystemCSPRNG) GenerateBytes(numBytes int) ([]byte, error) {
-	result := make([]byte, numBytes)
-	_, err := rand.Read(result)
-	return result, err
-}
\ No newline at end of file
@@ -1,56 +1,46 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"net/http"
-	"sync"
 	"time"
 )
 
-// WeatherCSPRNG implements a weather-based cryptographically secure pseudo-random number generator
-type WeatherCSPRNG struct {
-	state          []byte
-	counter        uint64
-	mutex          sync.Mutex
-	client         *http.Client
-	bytesGenerated int
-	lastReseed     time.Time
+const defaultWeatherEndpoint = "https://wttr.in/?format=j1"
+
+func init() {
+	RegisterGenerator("weather", func(opts map[string]string) (Generator, error) {
+		endpoint := defaultWeatherEndpoint
+		if v, ok := opts["endpoint"]; ok && v != "" {
+			endpoint = v
+		}
+		predictionResistance := opts["prediction_resistance"] == "true"
+		return newWeatherCSPRNG(endpoint, predictionResistance), nil
+	})
 }
 
-// NewWeatherCSPRNG creates a new weather-based CSPRNG
-func NewWeatherCSPRNG() *WeatherCSPRNG {
-	w := &WeatherCSPRNG{
-		client: &http.Client{Timeout: 1 * time.Second},
-	}
-	w.reseed()
-	return w
+// weatherEntropySource fetches weather data over HTTP and folds in request
+// timing, so a stuck endpoint still contributes some jitter-derived entropy.
+type weatherEntropySource struct {
+	client   *http.Client
+	endpoint string
 }
 
-// Name returns the generator name
-func (w *WeatherCSPRNG) Name() string {
-	return "Weather Based PRNG"
+// Name identifies this entropy source.
+func (s *weatherEntropySource) Name() string {
+	return "weather"
 }
 
-func (w *WeatherCSPRNG) reseed() {
-	newEntropy := w.getWeatherEntropy()
-
-	// Mix new entropy into the current state
-	mac := hmac.New(sha256.New, w.state)
-	mac.Write(newEntropy)
-	w.state = mac.Sum(nil)
-
-	w.lastReseed = time.Now()
-	w.bytesGenerated = 0
-}
-
-func (w *WeatherCSPRNG) getWeatherEntropy() []byte {
-	start := time.Now()
-	resp, err := w.client.Get("https://wttr.in/?format=j1")
-	duration := time.Since(start)
+// Gather fetches weather data as entropy. It returns the raw response body
+// plus timing, uncondensed, so EntropyHealth can actually see a stuck
+// endpoint's repeated output - hmacDRBG's own Update function already does
+// the HMAC-SHA256 conditioning a DRBG's entropy input needs, so hashing it
+// again here would only hide that repetition from the health tests.
+func (s *weatherEntropySource) Gather() []byte {
+	start := monotime()
+	resp, err := s.client.Get(s.endpoint)
+	duration := monotime() - start
 
 	var body []byte
 	if err == nil {
@@ -58,43 +48,41 @@ func (w *WeatherCSPRNG) getWeatherEntropy() []byte {
 		body, _ = io.ReadAll(resp.Body) // Error ignored for benchmark simplicity
 	}
 
-	entropy := fmt.Sprintf("%s|%d|%d", body, start.UnixNano(), duration.Nanoseconds())
-	hash := sha256.Sum256([]byte(entropy))
-	return hash[:]
+	return []byte(fmt.Sprintf("%s|%d|%d", body, start, duration))
 }
 
-// GenerateBytes generates cryptographically secure random bytes
-func (w *WeatherCSPRNG) GenerateBytes(numBytes int) ([]byte, error) {
-	w.mutex.Lock()
-	defer w.mutex.Unlock()
-
-	// Check if reseeding is required
-	if time.Since(w.lastReseed) > RESEED_INTERVAL || w.bytesGenerated > RESEED_BYTE_INTERVAL {
-		w.reseed()
-	}
-
-	result := make([]byte, numBytes)
-	generated := 0
+// MinEntropyBits is a conservative assessment of the raw HTTP response plus
+// request-timing noise this source draws on - a stuck endpoint returning
+// the same body every time contributes essentially none.
+func (s *weatherEntropySource) MinEntropyBits() float64 {
+	return 2
+}
 
-	for generated < numBytes {
-		mac := hmac.New(sha256.New, w.state)
-		counterBytes := make([]byte, 8)
-		binary.BigEndian.PutUint64(counterBytes, w.counter)
-		mac.Write(counterBytes)
-		block := mac.Sum(nil)
+// WeatherCSPRNG implements a weather-based cryptographically secure pseudo-random number generator
+type WeatherCSPRNG struct {
+	*hmacDRBG
+}
 
-		toCopy := min(len(block), numBytes-generated)
-		copy(result[generated:], block[:toCopy])
+// NewWeatherCSPRNG creates a new weather-based CSPRNG using the default wttr.in endpoint
+func NewWeatherCSPRNG() *WeatherCSPRNG {
+	return newWeatherCSPRNG(defaultWeatherEndpoint, false)
+}
 
-		generated += toCopy
-		w.counter++
+// NewWeatherCSPRNGWithEndpoint creates a new weather-based CSPRNG that sources
+// its entropy from the given HTTP endpoint instead of the default wttr.in one.
+func NewWeatherCSPRNGWithEndpoint(endpoint string) *WeatherCSPRNG {
+	return newWeatherCSPRNG(endpoint, false)
+}
 
-		// Update state for next block generation
-		updateMac := hmac.New(sha256.New, w.state)
-		updateMac.Write(block)
-		w.state = updateMac.Sum(nil)
+func newWeatherCSPRNG(endpoint string, predictionResistance bool) *WeatherCSPRNG {
+	source := &weatherEntropySource{
+		client:   &http.Client{Timeout: 1 * time.Second},
+		endpoint: endpoint,
 	}
+	return &WeatherCSPRNG{hmacDRBG: newHMACDRBG(source, predictionResistance)}
+}
 
-	w.bytesGenerated += numBytes
-	return result, nil
+// Name returns the generator name
+func (w *WeatherCSPRNG) Name() string {
+	return "Weather Based PRNG"
 }